@@ -54,6 +54,8 @@ type envConfig struct {
 	OAuthDiscovery string `envconfig:"OAUTH_DISCOVERY" required:"false"`
 	// The gitCommitId of the initial cloud event, for older Keptn instances this might be empty
 	GitCommitID string `envconfig:"GIT_COMMIT_ID"`
+	// The path the job volume is mounted at, the files of the task are copied into it
+	JobVolumeMountPath string `envconfig:"JOB_VOLUME_MOUNT_PATH" default:"/keptn"`
 }
 
 func main() {
@@ -128,7 +130,7 @@ func main() {
 		Keptn: resourceService,
 	}
 
-	err = file.MountFiles(env.Action, env.Task, env.GitCommitID, fs, jobConfigHandler)
+	err = file.MountFiles(env.Action, env.Task, env.GitCommitID, env.JobVolumeMountPath, fs, jobConfigHandler)
 	if err != nil {
 		log.Printf("Error while copying files: %s", err.Error())
 		os.Exit(-1)
@@ -2,18 +2,40 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"keptn-contrib/job-executor-service/pkg/config"
 	"keptn-contrib/job-executor-service/pkg/utils"
 	"log"
+	"os"
+	"path/filepath"
 )
 
 func main() {
 
+	// The migrate subcommand has its own set of flags and does not lint the configuration
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+
 	// Parse the allowPrivilegedJobs flag that can be changed to match the behavior of the job-executor-service
 	allowPrivilegedJobs := flag.Bool("allow-privileged-jobs", false,
 		"Set to true if you want to allow privileged job workloads")
 
+	// The stage and project configurations are only used if the given configuration inherits from them
+	stageConfigName := flag.String("stage-config", "",
+		"Path to the stage level job config, which is inherited by configurations with inherit: true")
+	projectConfigName := flag.String("project-config", "",
+		"Path to the project level job config, which is inherited by configurations with inherit: true")
+
+	// Included resources are read from the local filesystem, relative to the resource directory
+	resourceDir := flag.String("resource-dir", ".",
+		"Directory from which included resources are read, e.g. job/common/locust.yaml")
+
+	printEffectiveConfig := flag.Bool("print", false,
+		"Print the effective job config after all inherited configurations have been merged")
+
 	flag.Parse()
 
 	args := flag.Args()
@@ -21,21 +43,127 @@ func main() {
 		log.Fatal("exactly one argument needed")
 	}
 
+	jcr := config.JobConfigReader{
+		Keptn: localResourceService{directory: *resourceDir},
+	}
+
+	var configs []*config.Config
+	for _, jobConfigName := range []string{args[0], *stageConfigName, *projectConfigName} {
+		if jobConfigName == "" {
+			continue
+		}
+
+		conf, err := jcr.ResolveIncludes(readJobConfig(jobConfigName), "")
+		if err != nil {
+			log.Fatalf("error resolving includes of %v: %v", jobConfigName, err)
+		}
+
+		configs = append(configs, conf)
+	}
+
+	conf := config.ResolveInheritance(configs)
+
+	err := utils.VerifySecurityConfiguration(conf, *allowPrivilegedJobs)
+	if err != nil {
+		log.Fatalf("error processing security context: %v", err)
+	}
+
+	if *printEffectiveConfig {
+		// The effective config is always printed in the v2 schema, since it can express configs of all versions
+		effectiveConfig, err := conf.MarshalV2()
+		if err != nil {
+			log.Fatalf("unable to print effective config: %v", err)
+		}
+
+		fmt.Print(string(effectiveConfig))
+	}
+
+	log.Printf("config %v is valid", args[0])
+}
+
+// migrate rewrites a v2 job config to the v3 schema and prints the result or writes it back to the file
+func migrate(arguments []string) {
+	migrateFlags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	writeInPlace := migrateFlags.Bool("w", false, "Write the migrated job config back to the file instead of printing it")
+
+	// The error handling is done by the flag set itself, since it uses flag.ExitOnError
+	_ = migrateFlags.Parse(arguments)
+
+	args := migrateFlags.Args()
+	if len(args) != 1 {
+		log.Fatal("exactly one argument needed")
+	}
+
 	jobConfigName := args[0]
 	jobConfig, err := ioutil.ReadFile(jobConfigName)
 	if err != nil {
 		log.Fatalf("could not read job config %v: %v", jobConfigName, err)
 	}
 
-	conf, err := config.NewConfig(jobConfig)
+	migratedJobConfig, err := config.MigrateV2ToV3(jobConfig)
 	if err != nil {
-		log.Fatalf("error parsing %v: %v", string(jobConfig), err)
+		log.Fatalf("unable to migrate %v: %v", jobConfigName, err)
+	}
+
+	// Make sure that the migrated job config is still valid before it is written anywhere
+	if _, err := config.NewConfig(migratedJobConfig); err != nil {
+		log.Fatalf("migrated job config %v is invalid: %v", jobConfigName, err)
+	}
+
+	if !*writeInPlace {
+		fmt.Print(string(migratedJobConfig))
+		return
 	}
 
-	err = utils.VerifySecurityConfiguration(conf, *allowPrivilegedJobs)
+	fileInfo, err := os.Stat(jobConfigName)
 	if err != nil {
-		log.Fatalf("error processing security context: %v", err)
+		log.Fatalf("could not read job config %v: %v", jobConfigName, err)
+	}
+
+	err = ioutil.WriteFile(jobConfigName, migratedJobConfig, fileInfo.Mode())
+	if err != nil {
+		log.Fatalf("could not write job config %v: %v", jobConfigName, err)
+	}
+
+	log.Printf("config %v was migrated to %v", jobConfigName, config.APIVersionV3)
+}
+
+// readJobConfig reads and parses the job config from the given file and exits if the config is invalid
+func readJobConfig(jobConfigName string) *config.Config {
+	jobConfig, err := ioutil.ReadFile(jobConfigName)
+	if err != nil {
+		log.Fatalf("could not read job config %v: %v", jobConfigName, err)
 	}
 
-	log.Printf("config %v is valid", jobConfigName)
+	conf, err := config.NewConfig(jobConfig)
+	if err != nil {
+		log.Fatalf("error parsing %v: %v", string(jobConfig), err)
+	}
+
+	return conf
+}
+
+// localResourceService implements the config.KeptnResourceService by reading resources from a local directory
+type localResourceService struct {
+	directory string
+}
+
+// GetServiceResource reads the resource from the local directory
+func (l localResourceService) GetServiceResource(resource string, _ string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(l.directory, resource))
+}
+
+// GetProjectResource reads the resource from the local directory
+func (l localResourceService) GetProjectResource(resource string, _ string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(l.directory, resource))
+}
+
+// GetStageResource reads the resource from the local directory
+func (l localResourceService) GetStageResource(resource string, _ string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(l.directory, resource))
+}
+
+// GetAllKeptnResources is not needed for linting and therefore not supported
+func (l localResourceService) GetAllKeptnResources(resource string) (map[string][]byte, error) {
+	return nil, fmt.Errorf("reading all resources of %s is not supported", resource)
 }
@@ -62,6 +62,16 @@ type envConfig struct {
 	// FullDeploymentName is the name of the kubernetes deployment of the job executor service,
 	// it is used in managed-by labels for jobs and pods that are started by the service
 	FullDeploymentName string `envconfig:"FULL_DEPLOYMENT_NAME"`
+	// The default size limit of the volume the files of a task are mounted into
+	DefaultJobVolumeSize string `envconfig:"DEFAULT_JOB_VOLUME_SIZE" default:"20Mi"`
+	// The default path the volume with the files of a task is mounted at
+	DefaultJobVolumeMountPath string `envconfig:"DEFAULT_JOB_VOLUME_MOUNT_PATH" default:"/keptn"`
+	// The max size of the job volume a task can request, an empty value allows any size
+	MaxJobVolumeSize string `envconfig:"MAX_JOB_VOLUME_SIZE"`
+	// A list of the ConfigMaps and Secrets that can be mounted as volumes in jobs, e.g. configmap/*,secret/kubeconfig
+	AllowedVolumeSources string `envconfig:"ALLOWED_VOLUME_SOURCES" default:""`
+	// A comma separated list of the image pull secrets that are used by jobs in the job namespace
+	DefaultImagePullSecrets string `envconfig:"DEFAULT_IMAGE_PULL_SECRETS" default:""`
 }
 
 // ServiceName specifies the current services name (e.g., used as source when sending CloudEvents)
@@ -76,6 +86,9 @@ const podSecurityContextFilePath = "/config/job-podSecurityContext.json"
 // jobLabelFilePath describes the path of the job labels yaml file
 const jobLabelFilePath = "/config/job-labels.yaml"
 
+// jobSchedulingFilePath describes the path of the job scheduling config file that is defined in the deployment.yaml
+const jobSchedulingFilePath = "/config/job-scheduling.json"
+
 // DefaultResourceRequirements contains the default k8s resource requirements for the job and initcontainer, parsed on
 // startup from env (treat as const)
 var /* const */ DefaultResourceRequirements *v1.ResourceRequirements
@@ -93,6 +106,18 @@ var /* const */ JobLabels map[string]string
 // TaskDeadlineSecondsPtr represents the max duration of a task run, no limit if nil
 var TaskDeadlineSecondsPtr *int64
 
+// JobVolumeSettings contains the defaults and the max size of the job volume, parsed on startup from env
+// (treat as const)
+var /* const */ JobVolumeSettings *k8sutils.JobVolumeSettings
+
+// AllowedVolumeSources contains the ConfigMaps and Secrets that tasks are allowed to mount, parsed on startup from env
+// (treat as const)
+var /* const */ AllowedVolumeSources *utils.VolumeSourceAllowList
+
+// JobScheduling contains the default scheduling fields of jobs and the values tasks are allowed to use, parsed on
+// startup from the job scheduling config file (treat as const)
+var /* const */ JobScheduling *k8sutils.JobSchedulingSettings
+
 const serviceName = "job-executor-service"
 const eventWildcard = "*"
 
@@ -117,6 +142,10 @@ func NewEventHandler(allowList *utils.ImageFilterList) *eventhandler.EventHandle
 			JobLabels:                   JobLabels,
 			TaskDeadlineSeconds:         TaskDeadlineSecondsPtr,
 			JesDeploymentName:           env.FullDeploymentName,
+			JobVolumeSettings:           JobVolumeSettings,
+			AllowedVolumeSources:        AllowedVolumeSources,
+			JobScheduling:               JobScheduling,
+			DefaultImagePullSecrets:     k8sutils.CreateImagePullSecrets(env.DefaultImagePullSecrets),
 		},
 		K8s: k8sutils.NewK8s(""), // FIXME Why do we pass a namespace if it's ignored?
 	}
@@ -166,6 +195,25 @@ func main() {
 		log.Fatalf("Failed to read job labels: %s", err.Error())
 	}
 
+	JobVolumeSettings, err = k8sutils.CreateJobVolumeSettings(
+		env.DefaultJobVolumeSize,
+		env.DefaultJobVolumeMountPath,
+		env.MaxJobVolumeSize,
+	)
+	if err != nil {
+		log.Fatalf("unable to create job volume settings: %v", err.Error())
+	}
+
+	AllowedVolumeSources, err = utils.BuildVolumeSourceAllowList(env.AllowedVolumeSources)
+	if err != nil {
+		log.Fatalf("failed to generate the volume source allowlist: %v", err)
+	}
+
+	JobScheduling, err = k8sutils.ReadJobSchedulingSettings(jobSchedulingFilePath)
+	if err != nil {
+		log.Fatalf("unable to read job scheduling settings: %v", err.Error())
+	}
+
 	if env.TaskDeadlineSeconds > 0 {
 		TaskDeadlineSecondsPtr = &env.TaskDeadlineSeconds
 	}
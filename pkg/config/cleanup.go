@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+)
+
+// AllTasks returns the tasks, the onFailure tasks and the finally tasks of the action in the order they are executed.
+// The position of a task in this list is its task index
+func (a *Action) AllTasks() []Task {
+	tasks := make([]Task, 0, len(a.Tasks)+len(a.OnFailure)+len(a.Finally))
+	tasks = append(tasks, a.Tasks...)
+	tasks = append(tasks, a.OnFailure...)
+	tasks = append(tasks, a.Finally...)
+
+	return tasks
+}
+
+// validateCleanupTasks checks that onFailure and finally tasks don't use dependsOn and that their names don't clash
+// with other tasks of the action, since tasks are looked up by their name when the job is started
+func (a *Action) validateCleanupTasks() error {
+	if len(a.OnFailure) == 0 && len(a.Finally) == 0 {
+		return nil
+	}
+
+	names := make(map[string]bool, len(a.Tasks))
+	for _, task := range a.Tasks {
+		names[task.Name] = true
+	}
+
+	for _, task := range a.AllTasks()[len(a.Tasks):] {
+		if len(task.DependsOn) > 0 {
+			return fmt.Errorf("onFailure and finally task %s can't use dependsOn", task.Name)
+		}
+
+		if names[task.Name] {
+			return fmt.Errorf("task names must be unique if onFailure or finally is used, found task %s twice", task.Name)
+		}
+		names[task.Name] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanupTasks(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+taskTemplates:
+  kubectl:
+    image: "bitnami/kubectl"
+actions:
+  - name: "Run load tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Load tests"
+        image: "locustio/locust"
+    onFailure:
+      - name: "Collect diagnostics"
+        extends: kubectl
+        args: ["describe", "pods"]
+    finally:
+      - name: "Delete test data"
+        extends: kubectl
+        args: ["delete", "configmap", "test-data"]
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Run load tests")
+	require.True(t, found)
+
+	assert.Equal(t, []string{"Load tests", "Collect diagnostics", "Delete test data"}, taskNames(action.AllTasks()))
+
+	found, diagnostics := action.FindTaskByName("Collect diagnostics")
+	require.True(t, found)
+	assert.Equal(t, "bitnami/kubectl", diagnostics.Image)
+
+	found, cleanup := action.FindTaskByName("Delete test data")
+	require.True(t, found)
+	assert.Equal(t, "bitnami/kubectl", cleanup.Image)
+	assert.Equal(t, []string{"delete", "configmap", "test-data"}, cleanup.Args)
+}
+
+func TestCleanupTasksV3(t *testing.T) {
+	configYaml := `
+apiVersion: v3
+actions:
+  - name: "Run load tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Load tests"
+        image: "locustio/locust"
+    onFailure:
+      - name: "Collect diagnostics"
+        image: "bitnami/kubectl"
+    finally:
+      - name: "Delete test data"
+        image: "bitnami/kubectl"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Run load tests")
+	require.True(t, found)
+	assert.Equal(t, []string{"Load tests", "Collect diagnostics", "Delete test data"}, taskNames(action.AllTasks()))
+}
+
+func TestInvalidCleanupTasks(t *testing.T) {
+	tests := []struct {
+		name          string
+		cleanupYaml   string
+		expectedError string
+	}{
+		{
+			name: "duplicate task name",
+			cleanupYaml: `
+    finally:
+      - name: "Load tests"
+        image: "alpine"`,
+			expectedError: "invalid action Run load tests: task names must be unique if onFailure or finally is used, found task Load tests twice",
+		},
+		{
+			name: "dependsOn",
+			cleanupYaml: `
+    onFailure:
+      - name: "Collect diagnostics"
+        image: "alpine"
+        dependsOn: ["Load tests"]`,
+			expectedError: "invalid action Run load tests: onFailure and finally task Collect diagnostics can't use dependsOn",
+		},
+		{
+			name: "invalid if expression",
+			cleanupYaml: `
+    finally:
+      - name: "Delete test data"
+        image: "alpine"
+        if: "success() &&"`,
+			expectedError: "invalid task Delete test data in action Run load tests",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run load tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Load tests"
+        image: "locustio/locust"` + test.cleanupYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func taskNames(tasks []Task) []string {
+	names := make([]string, len(tasks))
+	for index, task := range tasks {
+		names[index] = task.Name
+	}
+
+	return names
+}
@@ -3,65 +3,168 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/gobwas/glob"
 	"gopkg.in/yaml.v2"
+)
 
-	"github.com/PaesslerAG/jsonpath"
+const (
+	// EventMatchModeExact matches only events with exactly the same type as the event name
+	EventMatchModeExact = "exact"
+	// EventMatchModeGlob matches the event type against the event name as glob pattern
+	EventMatchModeGlob = "glob"
+	// EventMatchModeRegex matches the event type against the event name as regular expression, the expression is
+	// anchored and must match the whole event type
+	EventMatchModeRegex = "regex"
 )
 
-const supportedAPIVersion = "v2"
+const (
+	// ActionFailureStrategyFailFast doesn't start any further tasks of an action after a task has failed (default)
+	ActionFailureStrategyFailFast = "failFast"
+	// ActionFailureStrategyWaitForAll runs all tasks of an action even if a task has failed
+	ActionFailureStrategyWaitForAll = "waitForAll"
+)
 
 // Config contains the configuration of the job-executor-service (job/config.yaml)
 type Config struct {
-	APIVersion *string  `yaml:"apiVersion"`
-	Actions    []Action `yaml:"actions"`
+	APIVersion    *string         `yaml:"apiVersion"`
+	Inherit       bool            `yaml:"inherit,omitempty"`
+	Include       []string        `yaml:"include,omitempty"`
+	TaskTemplates map[string]Task `yaml:"taskTemplates,omitempty"`
+	Actions       []Action        `yaml:"actions"`
 }
 
 // Action contains a action within the config which needs to be triggered
 type Action struct {
 	Name   string  `yaml:"name"`
 	Events []Event `yaml:"events"`
+	When   *When   `yaml:"when,omitempty"`
 	Tasks  []Task  `yaml:"tasks"`
-	Silent bool    `yaml:"silent"`
+	Silent bool    `yaml:"silent,omitempty"`
+
+	// Parallel runs all tasks of the action concurrently, Parallelism limits the number of concurrent tasks
+	Parallel        bool   `yaml:"parallel,omitempty"`
+	Parallelism     int    `yaml:"parallelism,omitempty"`
+	FailureStrategy string `yaml:"failureStrategy,omitempty"`
+
+	// OnFailure tasks run after the tasks if one of them failed, Finally tasks always run at the end of the action
+	OnFailure []Task `yaml:"onFailure,omitempty"`
+	Finally   []Task `yaml:"finally,omitempty"`
+
+	Workspace *Workspace `yaml:"workspace,omitempty"`
 }
 
-// Event defines a keptn event which determines if an Action should be triggered
+// Event defines a keptn event which determines if an Action should be triggered. Without a MatchMode the name is
+// treated like in previous releases, as a regular expression that only has to match a part of the event type
 type Event struct {
-	Name     string   `yaml:"name"`
-	JSONPath JSONPath `yaml:"jsonpath,omitempty"`
+	Name      string      `yaml:"name"`
+	MatchMode string      `yaml:"matchMode,omitempty"`
+	JSONPath  JSONPath    `yaml:"jsonpath,omitempty"`
+	All       []Condition `yaml:"all,omitempty"`
+	Any       []Condition `yaml:"any,omitempty"`
+	Not       *Condition  `yaml:"not,omitempty"`
+
+	// nameMatcher contains the compiled event name pattern, it is populated by NewConfig
+	nameMatcher stringMatcher
+}
+
+// stringMatcher is implemented by the compiled event name patterns of the different match modes
+type stringMatcher interface {
+	Match(s string) bool
+}
+
+// exactMatcher matches only strings that are equal to itself
+type exactMatcher string
+
+// Match returns true if the string is equal to the matcher
+func (m exactMatcher) Match(s string) bool {
+	return string(m) == s
+}
+
+// regexMatcher wraps a regular expression, it only matches whole strings if the expression is anchored
+type regexMatcher struct {
+	regex *regexp.Regexp
+}
+
+// Match returns true if the regular expression matches the string
+func (m regexMatcher) Match(s string) bool {
+	return m.regex.MatchString(s)
+}
+
+// Condition is a boolean combination of JSONPath filters, exactly one of the fields must be set
+type Condition struct {
+	JSONPath *JSONPath   `yaml:"jsonpath,omitempty"`
+	All      []Condition `yaml:"all,omitempty"`
+	Any      []Condition `yaml:"any,omitempty"`
+	Not      *Condition  `yaml:"not,omitempty"`
 }
 
 // JSONPath defines a filter for an Event
 type JSONPath struct {
-	Property string `yaml:"property"`
-	Match    string `yaml:"match"`
+	Property string   `yaml:"property"`
+	Match    string   `yaml:"match"`
+	Operator string   `yaml:"operator,omitempty"`
+	Values   []string `yaml:"values,omitempty"`
+
+	// matcher contains the compiled pattern of the regex and glob operators, it is populated by NewConfig
+	matcher stringMatcher
 }
 
 // Task this is the actual task which can be triggered within an Action
 type Task struct {
 	Name                    string            `yaml:"name"`
-	Files                   []string          `yaml:"files"`
+	Extends                 string            `yaml:"extends,omitempty"`
+	DependsOn               []string          `yaml:"dependsOn,omitempty"`
+	If                      string            `yaml:"if,omitempty"`
+	Retries                 int               `yaml:"retries,omitempty"`
+	RetryBackoff            string            `yaml:"retryBackoff,omitempty"`
+	AllowFailure            bool              `yaml:"allowFailure,omitempty"`
+	Matrix                  *Matrix           `yaml:"matrix,omitempty"`
+	Outputs                 []string          `yaml:"outputs,omitempty"`
+	PublishResult           bool              `yaml:"publishResult,omitempty"`
+	ResultMapping           map[int]string    `yaml:"resultMapping,omitempty"`
+	Files                   []string          `yaml:"files,omitempty"`
+	Templated               bool              `yaml:"templated,omitempty"`
 	Image                   string            `yaml:"image"`
-	ImagePullPolicy         string            `yaml:"imagePullPolicy"`
-	Cmd                     []string          `yaml:"cmd"`
-	Args                    []string          `yaml:"args"`
-	Env                     []Env             `yaml:"env"`
-	Resources               *Resources        `yaml:"resources"`
-	WorkingDir              string            `yaml:"workingDir"`
-	MaxPollDuration         *int              `yaml:"maxPollDuration"`
-	Namespace               string            `yaml:"namespace"`
-	TTLSecondsAfterFinished *int32            `yaml:"ttlSecondsAfterFinished"`
+	ImagePullPolicy         string            `yaml:"imagePullPolicy,omitempty"`
+	ImagePullSecrets        []string          `yaml:"imagePullSecrets,omitempty"`
+	Cmd                     []string          `yaml:"cmd,omitempty"`
+	Args                    []string          `yaml:"args,omitempty"`
+	Env                     []Env             `yaml:"env,omitempty"`
+	Resources               *Resources        `yaml:"resources,omitempty"`
+	Volume                  *JobVolume        `yaml:"volume,omitempty"`
+	Volumes                 []Volume          `yaml:"volumes,omitempty"`
+	WorkingDir              string            `yaml:"workingDir,omitempty"`
+	MaxPollDuration         *int              `yaml:"maxPollDuration,omitempty"`
+	Namespace               string            `yaml:"namespace,omitempty"`
+	TTLSecondsAfterFinished *int32            `yaml:"ttlSecondsAfterFinished,omitempty"`
 	SecurityContext         SecurityContext   `yaml:"securityContext,omitempty"`
 	ServiceAccount          *string           `yaml:"serviceAccount,omitempty"`
 	Annotations             map[string]string `yaml:"annotations,omitempty"`
+	NodeSelector            map[string]string `yaml:"nodeSelector,omitempty"`
+	Tolerations             []Toleration      `yaml:"tolerations,omitempty"`
+	Affinity                *Affinity         `yaml:"affinity,omitempty"`
+	PriorityClassName       string            `yaml:"priorityClassName,omitempty"`
+	RuntimeClassName        string            `yaml:"runtimeClassName,omitempty"`
 }
 
 // Env value from the event which will be added as env to the job
 type Env struct {
-	Name       string `yaml:"name"`
-	Value      string `yaml:"value"`
-	ValueFrom  string `yaml:"valueFrom"`
-	Formatting string `yaml:"as"`
+	Name       string   `yaml:"name"`
+	Value      string   `yaml:"value"`
+	ValueFrom  string   `yaml:"valueFrom"`
+	Formatting string   `yaml:"as"`
+	Keys       []EnvKey `yaml:"keys,omitempty"`
+	Prefix     string   `yaml:"prefix,omitempty"`
+}
+
+// EnvKey selects a single key of a Secret or ConfigMap which is added as env to the job, the env is named after the
+// key unless a different name is given with as
+type EnvKey struct {
+	Key string `yaml:"key"`
+	As  string `yaml:"as,omitempty"`
 }
 
 // Resources defines the resource requirements of a task
@@ -112,23 +215,193 @@ type SeccompProfile struct {
 	LocalhostProfile *string `yaml:"localhostProfile,omitempty"`
 }
 
-// NewConfig creates a new configuration from the provided config file content
+// NewConfig creates a new configuration from the provided config file content. The content is parsed according to
+// the schema of its apiVersion and normalized into the Config model
 func NewConfig(yamlContent []byte) (*Config, error) {
 
-	config := Config{}
-	err := yaml.UnmarshalStrict(yamlContent, &config)
+	// Only the apiVersion is read first, to select the parser for the schema of the configuration
+	versionHeader := struct {
+		APIVersion *string `yaml:"apiVersion"`
+	}{}
+	err := yaml.Unmarshal(yamlContent, &versionHeader)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if config.APIVersion == nil {
+	if versionHeader.APIVersion == nil {
 		return nil, fmt.Errorf("apiVersion must be specified")
-	} else if *config.APIVersion != supportedAPIVersion {
-		return nil, fmt.Errorf("apiVersion %v is not supported, use %v", *config.APIVersion, supportedAPIVersion)
 	}
 
-	return &config, nil
+	parseConfig, ok := configParsers[*versionHeader.APIVersion]
+	if !ok {
+		return nil, fmt.Errorf("apiVersion %v is not supported, use one of %v", *versionHeader.APIVersion,
+			strings.Join(SupportedAPIVersions(), ", "))
+	}
+
+	// Task templates are resolved before the configuration is parsed, such that all tasks are complete afterwards
+	yamlContent, err = resolveTaskTemplates(yamlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := parseConfig(yamlContent)
+	if err != nil {
+		return nil, err
+	}
+
+	for actionIndex := range config.Actions {
+		action := &config.Actions[actionIndex]
+		if err := action.validateExecution(); err != nil {
+			return nil, fmt.Errorf("invalid action %s: %w", action.Name, err)
+		}
+
+		if err := action.validateDependencies(); err != nil {
+			return nil, fmt.Errorf("invalid action %s: %w", action.Name, err)
+		}
+
+		if err := action.validateCleanupTasks(); err != nil {
+			return nil, fmt.Errorf("invalid action %s: %w", action.Name, err)
+		}
+
+		if err := action.validateWorkspace(); err != nil {
+			return nil, fmt.Errorf("invalid action %s: %w", action.Name, err)
+		}
+
+		if action.When != nil {
+			if err := action.When.validate(); err != nil {
+				return nil, fmt.Errorf("invalid when in action %s: %w", action.Name, err)
+			}
+		}
+
+		for eventIndex := range action.Events {
+			event := &action.Events[eventIndex]
+			if err := event.validate(); err != nil {
+				return nil, fmt.Errorf("invalid event %s in action %s: %w", event.Name, action.Name, err)
+			}
+		}
+
+		for _, task := range action.AllTasks() {
+			if _, ok := config.TaskTemplates[task.Extends]; task.Extends != "" && !ok {
+				return nil, fmt.Errorf("task %s in action %s extends unknown template %s", task.Name, action.Name, task.Extends)
+			}
+
+			if err := task.validateTemplates(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateIfExpression(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateRetries(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateOutputs(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validatePublishResult(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateResultMapping(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateEnv(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateImagePullSecrets(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateScheduling(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateVolume(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if err := task.validateVolumes(); err != nil {
+				return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+			}
+
+			if task.Matrix != nil {
+				if err := task.Matrix.validate(); err != nil {
+					return nil, fmt.Errorf("invalid task %s in action %s: %w", task.Name, action.Name, err)
+				}
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// Merge returns a new configuration which contains the actions of the config and the actions of the child
+// configuration. Actions of the child replace actions with the same name, all other actions of the child are appended
+func (c *Config) Merge(child *Config) *Config {
+	merged := &Config{
+		APIVersion: child.APIVersion,
+		Actions:    make([]Action, len(c.Actions)),
+	}
+	copy(merged.Actions, c.Actions)
+
+	// The tasks of both configurations are already resolved, the templates are only kept to describe the configuration
+	for _, templates := range []map[string]Task{c.TaskTemplates, child.TaskTemplates} {
+		for name, template := range templates {
+			if merged.TaskTemplates == nil {
+				merged.TaskTemplates = map[string]Task{}
+			}
+			merged.TaskTemplates[name] = template
+		}
+	}
+
+	for _, childAction := range child.Actions {
+		replaced := false
+		for index, action := range merged.Actions {
+			if action.Name == childAction.Name {
+				merged.Actions[index] = childAction
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			merged.Actions = append(merged.Actions, childAction)
+		}
+	}
+
+	return merged
+}
+
+// ResolveInheritance builds the effective configuration from a list of configurations that is ordered from the most
+// specific (service) to the least specific (project) configuration. The actions of the next configuration in the
+// list are only inherited if the configuration has set inherit to true
+func ResolveInheritance(configs []*Config) *Config {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	inheritedConfigs := 1
+	for inheritedConfigs < len(configs) && configs[inheritedConfigs-1].Inherit {
+		inheritedConfigs++
+	}
+
+	// Without inheritance the configuration is returned as is, to avoid copying the actions
+	if inheritedConfigs == 1 {
+		return configs[0]
+	}
+
+	effectiveConfig := configs[inheritedConfigs-1]
+	for index := inheritedConfigs - 2; index >= 0; index-- {
+		effectiveConfig = effectiveConfig.Merge(configs[index])
+	}
+
+	return effectiveConfig
 }
 
 // IsEventMatch indicated whether a given event matches the config
@@ -145,27 +418,204 @@ func (c *Config) IsEventMatch(eventType string, jsonEventData interface{}) bool
 // IsEventMatch indicated whether a given event matches the action
 func (a *Action) IsEventMatch(eventType string, jsonEventData interface{}) bool {
 
+	if a.When != nil && !a.When.IsMatch(jsonEventData) {
+		return false
+	}
+
 	for _, event := range a.Events {
-		// does the event type match with regex?
-		matched, _ := regexp.MatchString(event.Name, eventType)
-		if matched {
+		if event.isNameMatch(eventType) && event.isDataMatch(jsonEventData) {
+			return true
+		}
+	}
 
-			// no JSONPath specified, just match event type
-			if event.JSONPath.Property == "" {
-				return true
-			}
+	return false
+}
 
-			value, err := jsonpath.Get(event.JSONPath.Property, jsonEventData)
-			if err != nil {
-				continue
-			}
+// isNameMatch checks if the event type matches the name of the event according to the match mode. If the event
+// was not created by NewConfig, the name pattern is compiled on the fly and invalid patterns never match
+func (e *Event) isNameMatch(eventType string) bool {
+	matcher := e.nameMatcher
+	if matcher == nil {
+		var err error
+		matcher, err = e.compileNameMatcher()
+		if err != nil {
+			return false
+		}
+	}
 
-			if value == event.JSONPath.Match {
-				return true
-			}
+	return matcher.Match(eventType)
+}
+
+// compileNameMatcher compiles the event name into a matcher for the configured match mode
+func (e *Event) compileNameMatcher() (stringMatcher, error) {
+	switch e.MatchMode {
+	case EventMatchModeExact:
+		return exactMatcher(e.Name), nil
+	case "":
+		regex, err := regexp.Compile(e.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %s: %w", e.Name, err)
+		}
+		return regexMatcher{regex: regex}, nil
+	case EventMatchModeGlob:
+		pattern, err := glob.Compile(e.Name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %w", e.Name, err)
+		}
+		return pattern, nil
+	case EventMatchModeRegex:
+		regex, err := regexp.Compile("^(?:" + e.Name + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %s: %w", e.Name, err)
+		}
+		return regexMatcher{regex: regex}, nil
+	}
+
+	return nil, fmt.Errorf("unknown matchMode %s, use one of %s, %s or %s", e.MatchMode,
+		EventMatchModeExact, EventMatchModeGlob, EventMatchModeRegex)
+}
+
+// isDataMatch checks the JSONPath filter and the all, any and not conditions of the event, all given filters
+// have to match for the event to be considered a match
+func (e *Event) isDataMatch(jsonEventData interface{}) bool {
+
+	// an empty property means that no JSONPath filter is specified
+	if e.JSONPath.Property != "" && !e.JSONPath.IsMatch(jsonEventData) {
+		return false
+	}
+
+	return isAllMatch(e.All, jsonEventData) && isAnyMatch(e.Any, jsonEventData) &&
+		(e.Not == nil || !e.Not.IsMatch(jsonEventData))
+}
+
+// validate compiles the event name pattern and checks that the JSONPath filter and all conditions of the event are
+// well-formed
+func (e *Event) validate() error {
+	nameMatcher, err := e.compileNameMatcher()
+	if err != nil {
+		return err
+	}
+	e.nameMatcher = nameMatcher
+
+	if e.JSONPath.Property != "" {
+		if err := e.JSONPath.validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateConditionGroups(e.All, e.Any); err != nil {
+		return err
+	}
+
+	conditions := append(append([]Condition{}, e.All...), e.Any...)
+	if e.Not != nil {
+		conditions = append(conditions, *e.Not)
+	}
+
+	for _, condition := range conditions {
+		if err := condition.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsMatch indicates whether the given event data satisfies the condition
+func (c *Condition) IsMatch(jsonEventData interface{}) bool {
+	switch {
+	case c.JSONPath != nil:
+		return c.JSONPath.IsMatch(jsonEventData)
+	case c.All != nil:
+		return isAllMatch(c.All, jsonEventData)
+	case c.Any != nil:
+		return isAnyMatch(c.Any, jsonEventData)
+	case c.Not != nil:
+		return !c.Not.IsMatch(jsonEventData)
+	}
+
+	return false
+}
+
+// validate checks that exactly one field is set in the condition and all nested conditions
+func (c *Condition) validate() error {
+	setFields := 0
+	var nested []Condition
+
+	if c.JSONPath != nil {
+		setFields++
+		if c.JSONPath.Property == "" {
+			return fmt.Errorf("jsonpath condition must specify a property")
+		}
+		if err := c.JSONPath.validate(); err != nil {
+			return err
+		}
+	}
+	if c.All != nil {
+		setFields++
+		nested = append(nested, c.All...)
+	}
+	if c.Any != nil {
+		setFields++
+		nested = append(nested, c.Any...)
+	}
+	if c.Not != nil {
+		setFields++
+		nested = append(nested, *c.Not)
+	}
+
+	if setFields != 1 {
+		return fmt.Errorf("a condition must contain exactly one of jsonpath, all, any or not")
+	}
+
+	if err := validateConditionGroups(c.All, c.Any); err != nil {
+		return err
+	}
+
+	for _, condition := range nested {
+		if err := condition.validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateConditionGroups checks that the all and any groups contain at least one condition if they are specified,
+// since an empty all would always match and an empty any is most likely a mistake as well
+func validateConditionGroups(allConditions []Condition, anyConditions []Condition) error {
+	if allConditions != nil && len(allConditions) == 0 {
+		return fmt.Errorf("all must contain at least one condition")
+	}
+
+	if anyConditions != nil && len(anyConditions) == 0 {
+		return fmt.Errorf("any must contain at least one condition")
+	}
+
+	return nil
+}
+
+// isAllMatch returns true if all given conditions match, an empty list always matches
+func isAllMatch(conditions []Condition, jsonEventData interface{}) bool {
+	for _, condition := range conditions {
+		if !condition.IsMatch(jsonEventData) {
+			return false
 		}
 	}
+	return true
+}
+
+// isAnyMatch returns true if at least one of the given conditions matches, an empty list always matches
+func isAnyMatch(conditions []Condition, jsonEventData interface{}) bool {
+	if len(conditions) == 0 {
+		return true
+	}
 
+	for _, condition := range conditions {
+		if condition.IsMatch(jsonEventData) {
+			return true
+		}
+	}
 	return false
 }
 
@@ -180,10 +630,88 @@ func (c *Config) FindActionByName(actionName string) (bool, *Action) {
 	return false, nil
 }
 
-// FindTaskByName searches for a given Task by a provided name within the config
+// MaxParallelTasks returns the number of tasks of the action that may run at the same time
+func (a *Action) MaxParallelTasks() int {
+	if a.Parallelism > 0 {
+		return a.Parallelism
+	}
+
+	if (a.Parallel || a.HasTaskDependencies()) && len(a.Tasks) > 0 {
+		return len(a.Tasks)
+	}
+
+	return 1
+}
+
+// IsFailFast indicates whether the remaining tasks of the action should not be started after a task has failed
+func (a *Action) IsFailFast() bool {
+	return a.FailureStrategy != ActionFailureStrategyWaitForAll
+}
+
+// validateExecution checks the parallelism and the failure strategy of the action
+func (a *Action) validateExecution() error {
+	if a.Parallelism < 0 {
+		return fmt.Errorf("parallelism must not be negative")
+	}
+
+	switch a.FailureStrategy {
+	case "", ActionFailureStrategyFailFast, ActionFailureStrategyWaitForAll:
+		return nil
+	}
+
+	return fmt.Errorf("unknown failureStrategy %s, use one of %s or %s", a.FailureStrategy,
+		ActionFailureStrategyFailFast, ActionFailureStrategyWaitForAll)
+}
+
+// GetRetryBackoff returns the time to wait before a failed task is retried, invalid durations are treated as no backoff
+func (t *Task) GetRetryBackoff() time.Duration {
+	if t.RetryBackoff == "" {
+		return 0
+	}
+
+	retryBackoff, err := time.ParseDuration(t.RetryBackoff)
+	if err != nil {
+		return 0
+	}
+
+	return retryBackoff
+}
+
+// maxRetries is the maximum number of retries of a task, the names of the jobs support up to 999 attempts
+const maxRetries = 998
+
+// validateRetries checks that the number of retries is within the supported range and that the retry backoff is a
+// valid duration
+func (t *Task) validateRetries() error {
+	if t.Retries < 0 {
+		return fmt.Errorf("retries must not be negative")
+	}
+
+	if t.Retries > maxRetries {
+		return fmt.Errorf("retries must not exceed %d", maxRetries)
+	}
+
+	if t.RetryBackoff == "" {
+		return nil
+	}
+
+	retryBackoff, err := time.ParseDuration(t.RetryBackoff)
+	if err != nil {
+		return fmt.Errorf("invalid retryBackoff %s: %w", t.RetryBackoff, err)
+	}
+
+	if retryBackoff < 0 {
+		return fmt.Errorf("retryBackoff must not be negative")
+	}
+
+	return nil
+}
+
+// FindTaskByName searches for a given Task by a provided name within the tasks, onFailure and finally tasks of the
+// action
 func (a *Action) FindTaskByName(taskName string) (bool, *Task) {
 
-	for _, task := range a.Tasks {
+	for _, task := range a.AllTasks() {
 		if taskName == task.Name {
 			return true, &task
 		}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -565,3 +566,492 @@ actions:
 	require.NotNil(t, config.Actions[0].Tasks[2].TTLSecondsAfterFinished)
 	assert.Equal(t, *config.Actions[0].Tasks[2].TTLSecondsAfterFinished, int32(0))
 }
+
+func TestConditionMatch(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run locust outside of production"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        all:
+          - jsonpath:
+              property: "$.test.teststrategy"
+              match: "locust"
+          - not:
+              jsonpath:
+                property: "$.stage"
+                match: "production"
+        any:
+          - jsonpath:
+              property: "$.service"
+              match: "carts"
+          - jsonpath:
+              property: "$.service"
+              match: "orders"
+    tasks:
+      - name: "Run locust"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		data     map[string]interface{}
+		expected bool
+	}{
+		{
+			name:     "all conditions match",
+			data:     map[string]interface{}{"stage": "dev", "service": "carts", "test": map[string]interface{}{"teststrategy": "locust"}},
+			expected: true,
+		},
+		{
+			name:     "second any condition matches",
+			data:     map[string]interface{}{"stage": "dev", "service": "orders", "test": map[string]interface{}{"teststrategy": "locust"}},
+			expected: true,
+		},
+		{
+			name:     "not condition fails",
+			data:     map[string]interface{}{"stage": "production", "service": "carts", "test": map[string]interface{}{"teststrategy": "locust"}},
+			expected: false,
+		},
+		{
+			name:     "all condition fails",
+			data:     map[string]interface{}{"stage": "dev", "service": "carts", "test": map[string]interface{}{"teststrategy": "health"}},
+			expected: false,
+		},
+		{
+			name:     "no any condition matches",
+			data:     map[string]interface{}{"stage": "dev", "service": "payment", "test": map[string]interface{}{"teststrategy": "locust"}},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, config.IsEventMatch("sh.keptn.event.test.triggered", test.data))
+		})
+	}
+}
+
+func TestInvalidCondition(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Action with an invalid condition"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        all:
+          - jsonpath:
+              property: "$.test.teststrategy"
+              match: "locust"
+            not:
+              jsonpath:
+                property: "$.stage"
+                match: "production"
+    tasks:
+      - name: "Run locust"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.Error(t, err)
+	assert.Nil(t, config)
+}
+
+func TestEmptyConditionGroups(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventYaml     string
+		expectedError string
+	}{
+		{
+			name: "empty all",
+			eventYaml: `
+        all: []`,
+			expectedError: "all must contain at least one condition",
+		},
+		{
+			name: "empty any",
+			eventYaml: `
+        any: []`,
+			expectedError: "any must contain at least one condition",
+		},
+		{
+			name: "empty nested all",
+			eventYaml: `
+        not:
+          all: []`,
+			expectedError: "all must contain at least one condition",
+		},
+		{
+			name: "empty nested any",
+			eventYaml: `
+        all:
+          - any: []`,
+			expectedError: "any must contain at least one condition",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Action"
+    events:
+      - name: "sh.keptn.event.test.triggered"` + test.eventYaml + `
+    tasks:
+      - name: "task"
+        image: "alpine"
+`
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestEventMatchModes(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventName string
+		matchMode string
+		eventType string
+		expected  bool
+	}{
+		{
+			name:      "exact match",
+			eventName: "sh.keptn.event.test.triggered",
+			matchMode: EventMatchModeExact,
+			eventType: "sh.keptn.event.test.triggered",
+			expected:  true,
+		},
+		{
+			name:      "exact does not match suffix",
+			eventName: "sh.keptn.event.test.triggered",
+			matchMode: EventMatchModeExact,
+			eventType: "sh.keptn.event.test.triggered.foo",
+			expected:  false,
+		},
+		{
+			name:      "unanchored regex is the default",
+			eventName: "sh.keptn.event.*.triggered",
+			eventType: "sh.keptn.event.deployment.triggered",
+			expected:  true,
+		},
+		{
+			name:      "default matches regex alternatives",
+			eventName: "sh.keptn.event.(test|deployment).triggered",
+			eventType: "sh.keptn.event.deployment.triggered",
+			expected:  true,
+		},
+		{
+			name:      "default matches part of the event type",
+			eventName: "sh.keptn.event.test.triggered",
+			eventType: "sh.keptn.event.test.triggered.foo",
+			expected:  true,
+		},
+		{
+			name:      "glob does not match suffix",
+			eventName: "sh.keptn.event.test.triggered",
+			matchMode: EventMatchModeGlob,
+			eventType: "sh.keptn.event.test.triggered.foo",
+			expected:  false,
+		},
+		{
+			name:      "glob does not treat dots as wildcard",
+			eventName: "sh.keptn.event.test.triggered",
+			matchMode: EventMatchModeGlob,
+			eventType: "shXkeptnXeventXtestXtriggered",
+			expected:  false,
+		},
+		{
+			name:      "regex matches alternatives",
+			eventName: `sh\.keptn\.event\.(test|deployment)\.triggered`,
+			matchMode: EventMatchModeRegex,
+			eventType: "sh.keptn.event.deployment.triggered",
+			expected:  true,
+		},
+		{
+			name:      "regex is anchored",
+			eventName: `sh\.keptn\.event\.test\.triggered`,
+			matchMode: EventMatchModeRegex,
+			eventType: "sh.keptn.event.test.triggered.foo",
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Action"
+    events:
+      - name: '` + test.eventName + `'
+        matchMode: '` + test.matchMode + `'
+    tasks:
+      - name: "task"
+        image: "alpine"
+`
+			config, err := NewConfig([]byte(configYaml))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, config.IsEventMatch(test.eventType, nil))
+		})
+	}
+}
+
+func TestInvalidEventMatchMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventName string
+		matchMode string
+	}{
+		{
+			name:      "invalid regex",
+			eventName: "sh.keptn.event.(test.triggered",
+			matchMode: EventMatchModeRegex,
+		},
+		{
+			name:      "invalid default regex",
+			eventName: "sh.keptn.event.(test.triggered",
+			matchMode: "",
+		},
+		{
+			name:      "invalid glob",
+			eventName: "sh.keptn.event.[test.triggered",
+			matchMode: EventMatchModeGlob,
+		},
+		{
+			name:      "unknown match mode",
+			eventName: "sh.keptn.event.test.triggered",
+			matchMode: "fuzzy",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Action"
+    events:
+      - name: '` + test.eventName + `'
+        matchMode: '` + test.matchMode + `'
+    tasks:
+      - name: "task"
+        image: "alpine"
+`
+			config, err := NewConfig([]byte(configYaml))
+			assert.Error(t, err)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestV3ConfigUnmarshalling(t *testing.T) {
+	configYaml := `
+apiVersion: v3
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        condition:
+          jsonpath:
+            property: "$.test.teststrategy"
+            match: "locust"
+      - name: "sh.keptn.event.*.finished"
+        matchMode: glob
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+	require.Len(t, config.Actions, 1)
+	require.Len(t, config.Actions[0].Events, 2)
+
+	assert.Equal(t, EventMatchModeExact, config.Actions[0].Events[0].MatchMode)
+	require.Len(t, config.Actions[0].Events[0].All, 1)
+	assert.Equal(t, "$.test.teststrategy", config.Actions[0].Events[0].All[0].JSONPath.Property)
+	assert.Equal(t, EventMatchModeGlob, config.Actions[0].Events[1].MatchMode)
+
+	assert.True(t, config.IsEventMatch("sh.keptn.event.test.triggered", map[string]interface{}{
+		"test": map[string]interface{}{"teststrategy": "locust"},
+	}))
+	assert.True(t, config.IsEventMatch("sh.keptn.event.deployment.finished", nil))
+	assert.False(t, config.IsEventMatch("sh.keptn.event.test.triggered.foo", map[string]interface{}{
+		"test": map[string]interface{}{"teststrategy": "locust"},
+	}))
+}
+
+func TestV3ConfigRejectsV2Filters(t *testing.T) {
+	configYaml := `
+apiVersion: v3
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        jsonpath:
+          property: "$.test.teststrategy"
+          match: "locust"
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.Error(t, err)
+	assert.Nil(t, config)
+}
+
+func TestMarshalV2RoundTrip(t *testing.T) {
+	configYaml := `
+apiVersion: v3
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        condition:
+          jsonpath:
+            property: "$.test.teststrategy"
+            match: "locust"
+      - name: "sh.keptn.event.*.finished"
+        matchMode: glob
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+    onFailure:
+      - name: "Notify"
+        image: "alpine"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	marshaledConfig, err := config.MarshalV2()
+	require.NoError(t, err)
+
+	v2, err := NewConfig(marshaledConfig)
+	require.NoError(t, err)
+	assert.Equal(t, APIVersionV2, *v2.APIVersion)
+
+	remarshaledConfig, err := v2.MarshalV2()
+	require.NoError(t, err)
+	assert.Equal(t, string(marshaledConfig), string(remarshaledConfig))
+
+	for _, eventType := range []string{
+		"sh.keptn.event.test.triggered", "sh.keptn.event.deployment.finished", "sh.keptn.event.test.triggered.foo",
+	} {
+		eventData := map[string]interface{}{"test": map[string]interface{}{"teststrategy": "locust"}}
+		assert.Equal(t, config.IsEventMatch(eventType, eventData), v2.IsEventMatch(eventType, eventData), eventType)
+	}
+	assert.Equal(t, config.Actions[0].OnFailure, v2.Actions[0].OnFailure)
+}
+
+func TestActionParallelism(t *testing.T) {
+	tasks := []Task{{Name: "Lint"}, {Name: "Unit tests"}, {Name: "SAST"}}
+
+	assert.Equal(t, 1, (&Action{Tasks: tasks}).MaxParallelTasks())
+	assert.Equal(t, 3, (&Action{Tasks: tasks, Parallel: true}).MaxParallelTasks())
+	assert.Equal(t, 2, (&Action{Tasks: tasks, Parallel: true, Parallelism: 2}).MaxParallelTasks())
+	assert.Equal(t, 2, (&Action{Tasks: tasks, Parallelism: 2}).MaxParallelTasks())
+
+	assert.True(t, (&Action{}).IsFailFast())
+	assert.True(t, (&Action{FailureStrategy: ActionFailureStrategyFailFast}).IsFailFast())
+	assert.False(t, (&Action{FailureStrategy: ActionFailureStrategyWaitForAll}).IsFailFast())
+}
+
+func TestInvalidActionParallelism(t *testing.T) {
+	tests := []struct {
+		name          string
+		execution     string
+		expectedError string
+	}{
+		{
+			name:          "negative parallelism",
+			execution:     "parallelism: -1",
+			expectedError: "invalid action Run checks: parallelism must not be negative",
+		},
+		{
+			name:          "unknown failure strategy",
+			execution:     "failureStrategy: ignore",
+			expectedError: "invalid action Run checks: unknown failureStrategy ignore",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run checks"
+    ` + test.execution + `
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Lint"
+        image: "golangci/golangci-lint"
+`
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestTaskRetries(t *testing.T) {
+	assert.Equal(t, time.Duration(0), (&Task{}).GetRetryBackoff())
+	assert.Equal(t, 30*time.Second, (&Task{RetryBackoff: "30s"}).GetRetryBackoff())
+
+	tests := []struct {
+		name          string
+		retries       string
+		expectedError string
+	}{
+		{
+			name:          "negative retries",
+			retries:       "retries: -1",
+			expectedError: "invalid task Run tests in action Run tests: retries must not be negative",
+		},
+		{
+			name:          "too many retries",
+			retries:       "retries: 999",
+			expectedError: "invalid task Run tests in action Run tests: retries must not exceed 998",
+		},
+		{
+			name:          "invalid backoff",
+			retries:       "retryBackoff: 10 seconds",
+			expectedError: "invalid task Run tests in action Run tests: invalid retryBackoff 10 seconds",
+		},
+		{
+			name:          "negative backoff",
+			retries:       "retryBackoff: -10s",
+			expectedError: "invalid task Run tests in action Run tests: retryBackoff must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run tests"
+        image: "locustio/locust"
+        ` + test.retries
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
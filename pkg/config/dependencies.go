@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HasTaskDependencies indicates whether the tasks of the action form a dependency graph. In this case all tasks
+// without unmet dependencies are started together instead of running the tasks in the order they are listed
+func (a *Action) HasTaskDependencies() bool {
+	for _, task := range a.Tasks {
+		if len(task.DependsOn) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TaskDependencies returns the indices of the tasks each task of the action depends on
+func (a *Action) TaskDependencies() [][]int {
+	indices := make(map[string]int, len(a.Tasks))
+	for index, task := range a.Tasks {
+		indices[task.Name] = index
+	}
+
+	dependencies := make([][]int, len(a.Tasks))
+	for index, task := range a.Tasks {
+		for _, dependency := range task.DependsOn {
+			if dependencyIndex, ok := indices[dependency]; ok {
+				dependencies[index] = append(dependencies[index], dependencyIndex)
+			}
+		}
+	}
+
+	return dependencies
+}
+
+// validateDependencies checks that all dependencies of the tasks refer to other tasks of the action and that the
+// dependencies don't contain a cycle
+func (a *Action) validateDependencies() error {
+	if !a.HasTaskDependencies() {
+		return nil
+	}
+
+	names := make(map[string]bool, len(a.Tasks))
+	for _, task := range a.Tasks {
+		if names[task.Name] {
+			return fmt.Errorf("task names must be unique if dependsOn is used, found task %s twice", task.Name)
+		}
+		names[task.Name] = true
+	}
+
+	for _, task := range a.Tasks {
+		for _, dependency := range task.DependsOn {
+			if !names[dependency] {
+				return fmt.Errorf("task %s depends on unknown task %s", task.Name, dependency)
+			}
+		}
+	}
+
+	// Depth-first search over the dependencies, a task that is visited again while it is still on the path is part
+	// of a cycle
+	dependencies := a.TaskDependencies()
+	visited := make([]bool, len(a.Tasks))
+	onPath := make([]bool, len(a.Tasks))
+
+	var path []string
+	var visit func(index int) error
+	visit = func(index int) error {
+		path = append(path, a.Tasks[index].Name)
+		defer func() { path = path[:len(path)-1] }()
+
+		if onPath[index] {
+			start := 0
+			for path[start] != a.Tasks[index].Name {
+				start++
+			}
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(path[start:], " -> "))
+		}
+
+		if visited[index] {
+			return nil
+		}
+
+		visited[index] = true
+		onPath[index] = true
+		for _, dependency := range dependencies[index] {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		onPath[index] = false
+
+		return nil
+	}
+
+	for index := range a.Tasks {
+		if err := visit(index); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrecedingTasks returns the indices of the tasks that precede each task of the action. If the tasks form a
+// dependency graph, these are all tasks a task depends on directly or indirectly, otherwise all tasks that are listed
+// before the task
+func (a *Action) PrecedingTasks() [][]int {
+	preceding := make([][]int, len(a.Tasks))
+
+	if !a.HasTaskDependencies() {
+		for index := range a.Tasks {
+			for precedingIndex := 0; precedingIndex < index; precedingIndex++ {
+				preceding[index] = append(preceding[index], precedingIndex)
+			}
+		}
+
+		return preceding
+	}
+
+	dependencies := a.TaskDependencies()
+	for index := range a.Tasks {
+		visited := make([]bool, len(a.Tasks))
+		queue := append([]int{}, dependencies[index]...)
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			if visited[current] {
+				continue
+			}
+
+			visited[current] = true
+			queue = append(queue, dependencies[current]...)
+		}
+
+		for precedingIndex, isPreceding := range visited {
+			if isPreceding {
+				preceding[index] = append(preceding[index], precedingIndex)
+			}
+		}
+	}
+
+	return preceding
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDependencies(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Deploy"
+        image: "alpine/helm"
+        dependsOn: ["Unit tests", "Lint"]
+      - name: "Build"
+        image: "golang"
+      - name: "Unit tests"
+        image: "golang"
+        dependsOn: ["Build"]
+      - name: "Lint"
+        image: "golangci/golangci-lint"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	action := config.Actions[0]
+	assert.True(t, action.HasTaskDependencies())
+	assert.Equal(t, [][]int{{2, 3}, nil, {1}, nil}, action.TaskDependencies())
+	assert.Equal(t, 4, action.MaxParallelTasks())
+	assert.Equal(t, [][]int{{1, 2, 3}, nil, {1}, nil}, action.PrecedingTasks())
+}
+
+func TestPrecedingTasksWithoutDependencies(t *testing.T) {
+	action := Action{
+		Tasks: []Task{{Name: "Build"}, {Name: "Unit tests"}, {Name: "Deploy"}},
+	}
+
+	assert.Equal(t, [][]int{nil, {0}, {0, 1}}, action.PrecedingTasks())
+}
+
+func TestInvalidTaskDependencies(t *testing.T) {
+	tests := []struct {
+		name          string
+		tasks         string
+		expectedError string
+	}{
+		{
+			name: "unknown task",
+			tasks: `
+      - name: "Build"
+        image: "golang"
+      - name: "Deploy"
+        image: "alpine/helm"
+        dependsOn: ["Unit tests"]`,
+			expectedError: "invalid action Build and deploy: task Deploy depends on unknown task Unit tests",
+		},
+		{
+			name: "self dependency",
+			tasks: `
+      - name: "Build"
+        image: "golang"
+        dependsOn: ["Build"]`,
+			expectedError: "invalid action Build and deploy: dependency cycle detected: Build -> Build",
+		},
+		{
+			name: "cycle",
+			tasks: `
+      - name: "Build"
+        image: "golang"
+        dependsOn: ["Deploy"]
+      - name: "Unit tests"
+        image: "golang"
+        dependsOn: ["Build"]
+      - name: "Deploy"
+        image: "alpine/helm"
+        dependsOn: ["Unit tests"]`,
+			expectedError: "invalid action Build and deploy: dependency cycle detected: Build -> Deploy -> Unit tests -> Build",
+		},
+		{
+			name: "duplicate task names",
+			tasks: `
+      - name: "Build"
+        image: "golang"
+      - name: "Build"
+        image: "golang"
+      - name: "Deploy"
+        image: "alpine/helm"
+        dependsOn: ["Build"]`,
+			expectedError: "invalid action Build and deploy: task names must be unique if dependsOn is used, found task Build twice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:` + test.tasks
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.EqualError(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import "fmt"
+
+// GetName returns the name of the env the key is added as, without the prefix of the env
+func (k EnvKey) GetName() string {
+	if k.As == "" {
+		return k.Key
+	}
+
+	return k.As
+}
+
+// validateEnv checks that keys and prefix are only used for env from Secrets and ConfigMaps and that every selected
+// key results in a distinct env name
+func (t *Task) validateEnv() error {
+	for _, env := range t.Env {
+		if env.ValueFrom != "secret" && env.ValueFrom != "configmap" {
+			if len(env.Keys) > 0 || env.Prefix != "" {
+				return fmt.Errorf(
+					"env %s can only use keys and prefix with valueFrom secret or configmap", env.Name,
+				)
+			}
+
+			continue
+		}
+
+		names := make(map[string]bool, len(env.Keys))
+		for _, key := range env.Keys {
+			if key.Key == "" {
+				return fmt.Errorf("keys of env %s require a key", env.Name)
+			}
+
+			if names[key.GetName()] {
+				return fmt.Errorf("env %s selects more than one key as %s", env.Name, key.GetName())
+			}
+			names[key.GetName()] = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvKeys(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Integration tests"
+        image: "alpine"
+        env:
+          - name: "db-credentials"
+            valueFrom: "secret"
+            keys:
+              - key: "password"
+                as: "DB_PASSWORD"
+              - key: "username"
+          - name: "test-settings"
+            valueFrom: "configmap"
+            prefix: "TEST_"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, task := config.Actions[0].FindTaskByName("Integration tests")
+	require.True(t, found)
+	require.Len(t, task.Env, 2)
+
+	assert.Equal(t, []EnvKey{{Key: "password", As: "DB_PASSWORD"}, {Key: "username"}}, task.Env[0].Keys)
+	assert.Equal(t, "DB_PASSWORD", task.Env[0].Keys[0].GetName())
+	assert.Equal(t, "username", task.Env[0].Keys[1].GetName())
+	assert.Equal(t, "configmap", task.Env[1].ValueFrom)
+	assert.Equal(t, "TEST_", task.Env[1].Prefix)
+}
+
+func TestInvalidEnvKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		envYaml       string
+		expectedError string
+	}{
+		{
+			name: "keys with valueFrom string",
+			envYaml: `
+          - name: "HOST"
+            value: "localhost"
+            valueFrom: "string"
+            keys:
+              - key: "host"`,
+			expectedError: "env HOST can only use keys and prefix with valueFrom secret or configmap",
+		},
+		{
+			name: "prefix with valueFrom event",
+			envYaml: `
+          - name: "HOST"
+            value: "$.data.deployment.deploymentURIsLocal[0]"
+            valueFrom: "event"
+            prefix: "TEST_"`,
+			expectedError: "env HOST can only use keys and prefix with valueFrom secret or configmap",
+		},
+		{
+			name: "missing key",
+			envYaml: `
+          - name: "db-credentials"
+            valueFrom: "secret"
+            keys:
+              - as: "DB_PASSWORD"`,
+			expectedError: "keys of env db-credentials require a key",
+		},
+		{
+			name: "duplicate env name",
+			envYaml: `
+          - name: "db-credentials"
+            valueFrom: "secret"
+            keys:
+              - key: "password"
+                as: "PASSWORD"
+              - key: "PASSWORD"`,
+			expectedError: "env db-credentials selects more than one key as PASSWORD",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Integration tests"
+        image: "alpine"
+        env:` + test.envYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Integration tests in action Run tests: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// statusFunctionPattern matches the status functions that can be used in if expressions
+var statusFunctionPattern = regexp.MustCompile(`\b(success|failure|always)\s*\(`)
+
+// ifExpressionLanguage returns the language of if expressions, which supports JSONPath expressions on the event and
+// the status functions success(), failure() and always()
+func ifExpressionLanguage(precedingTaskFailed bool) gval.Language {
+	return gval.NewLanguage(
+		gval.Full(),
+		jsonpath.Language(),
+		gval.Function("success", func() bool { return !precedingTaskFailed }),
+		gval.Function("failure", func() bool { return precedingTaskFailed }),
+		gval.Function("always", func() bool { return true }),
+	)
+}
+
+// IsConditionMet evaluates the if expression of the task against the event. Like in GitHub Actions, an expression
+// that doesn't use a status function only runs the task if no preceding task failed. Tasks without an if expression
+// run if no preceding task failed
+func (t *Task) IsConditionMet(jsonEventData interface{}, precedingTaskFailed bool) (bool, error) {
+	if t.If == "" {
+		return !precedingTaskFailed, nil
+	}
+
+	if !statusFunctionPattern.MatchString(t.If) && precedingTaskFailed {
+		return false, nil
+	}
+
+	evaluable, err := ifExpressionLanguage(precedingTaskFailed).NewEvaluable(t.If)
+	if err != nil {
+		return false, fmt.Errorf("invalid if expression %s: %w", t.If, err)
+	}
+
+	value, err := evaluable(context.Background(), jsonEventData)
+	if err != nil {
+		return false, fmt.Errorf("unable to evaluate if expression %s: %w", t.If, err)
+	}
+
+	isMet, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("if expression %s must evaluate to a boolean, got %v", t.If, value)
+	}
+
+	return isMet, nil
+}
+
+// validateIfExpression checks that the if expression of the task can be parsed
+func (t *Task) validateIfExpression() error {
+	if t.If == "" {
+		return nil
+	}
+
+	if _, err := ifExpressionLanguage(false).NewEvaluable(t.If); err != nil {
+		return fmt.Errorf("invalid if expression %s: %w", t.If, err)
+	}
+
+	return nil
+}
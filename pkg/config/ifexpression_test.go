@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskIsConditionMet(t *testing.T) {
+	eventData := map[string]interface{}{
+		"data": map[string]interface{}{
+			"stage": "production",
+			"labels": map[string]interface{}{
+				"notify": "true",
+			},
+			"evaluation": map[string]interface{}{
+				"score": 85.0,
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		expression          string
+		precedingTaskFailed bool
+		expected            bool
+	}{
+		{name: "no expression", expression: "", expected: true},
+		{name: "no expression after failure", expression: "", precedingTaskFailed: true, expected: false},
+		{name: "success", expression: "success()", expected: true},
+		{name: "success after failure", expression: "success()", precedingTaskFailed: true, expected: false},
+		{name: "failure", expression: "failure()", expected: false},
+		{name: "failure after failure", expression: "failure()", precedingTaskFailed: true, expected: true},
+		{name: "always", expression: "always()", expected: true},
+		{name: "always after failure", expression: "always()", precedingTaskFailed: true, expected: true},
+		{name: "jsonpath", expression: `$.data.labels.notify == "true"`, expected: true},
+		{name: "jsonpath after failure", expression: `$.data.labels.notify == "true"`, precedingTaskFailed: true, expected: false},
+		{name: "jsonpath not met", expression: `$.data.stage != "production"`, expected: false},
+		{name: "numeric comparison", expression: `$.data.evaluation.score >= 80`, expected: true},
+		{name: "combined", expression: `failure() && $.data.stage == "production"`, precedingTaskFailed: true, expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := Task{Name: "Notify", If: test.expression}
+
+			isMet, err := task.IsConditionMet(eventData, test.precedingTaskFailed)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, isMet)
+		})
+	}
+}
+
+func TestTaskIsConditionMetErrors(t *testing.T) {
+	eventData := map[string]interface{}{
+		"data": map[string]interface{}{
+			"stage": "production",
+		},
+	}
+
+	task := Task{Name: "Notify", If: `$.data.labels.notify == "true"`}
+	_, err := task.IsConditionMet(eventData, false)
+	assert.ErrorContains(t, err, "unable to evaluate if expression")
+
+	task = Task{Name: "Notify", If: `$.data.stage`}
+	_, err = task.IsConditionMet(eventData, false)
+	assert.ErrorContains(t, err, "must evaluate to a boolean")
+}
+
+func TestInvalidIfExpression(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Notify"
+        image: "curlimages/curl"
+        if: "failure() &&"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.ErrorContains(t, err, "invalid task Notify in action Deploy: invalid if expression failure() &&")
+	assert.Nil(t, config)
+}
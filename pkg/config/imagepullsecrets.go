@@ -0,0 +1,29 @@
+package config
+
+import "fmt"
+
+// validateImagePullSecrets checks that the image pull secrets of the task are named and unique. Since the secrets are
+// looked up in the namespace of the job-executor-service jobs, they can't be used together with a custom namespace
+func (t *Task) validateImagePullSecrets() error {
+	if len(t.ImagePullSecrets) == 0 {
+		return nil
+	}
+
+	if t.Namespace != "" {
+		return fmt.Errorf("imagePullSecrets can't be used together with a custom namespace")
+	}
+
+	names := make(map[string]bool, len(t.ImagePullSecrets))
+	for _, name := range t.ImagePullSecrets {
+		if name == "" {
+			return fmt.Errorf("imagePullSecrets must not contain an empty name")
+		}
+
+		if names[name] {
+			return fmt.Errorf("imagePullSecrets contain %s twice", name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
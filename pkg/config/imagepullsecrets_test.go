@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImagePullSecrets(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Integration tests"
+        image: "registry.example.com/tests:1.0.0"
+        imagePullSecrets:
+          - "registry-credentials"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, task := config.Actions[0].FindTaskByName("Integration tests")
+	require.True(t, found)
+	assert.Equal(t, []string{"registry-credentials"}, task.ImagePullSecrets)
+}
+
+func TestInvalidImagePullSecrets(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskYaml      string
+		expectedError string
+	}{
+		{
+			name: "custom namespace",
+			taskYaml: `
+        namespace: "tests"
+        imagePullSecrets:
+          - "registry-credentials"`,
+			expectedError: "imagePullSecrets can't be used together with a custom namespace",
+		},
+		{
+			name: "empty name",
+			taskYaml: `
+        imagePullSecrets:
+          - ""`,
+			expectedError: "imagePullSecrets must not contain an empty name",
+		},
+		{
+			name: "duplicate name",
+			taskYaml: `
+        imagePullSecrets:
+          - "registry-credentials"
+          - "registry-credentials"`,
+			expectedError: "imagePullSecrets contain registry-credentials twice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Integration tests"
+        image: "registry.example.com/tests:1.0.0"` + test.taskYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Integration tests in action Run tests: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/gobwas/glob"
+)
+
+const (
+	// JSONPathOperatorEquals matches if the string representation of the value is equal to match (default)
+	JSONPathOperatorEquals = "equals"
+	// JSONPathOperatorRegex matches if the value matches the regular expression given in match, the expression is
+	// anchored and must match the whole value
+	JSONPathOperatorRegex = "regex"
+	// JSONPathOperatorGlob matches if the value matches the glob pattern given in match
+	JSONPathOperatorGlob = "glob"
+	// JSONPathOperatorIn matches if the value is equal to one of the entries in values
+	JSONPathOperatorIn = "in"
+	// JSONPathOperatorExists matches if the property is present in the event
+	JSONPathOperatorExists = "exists"
+	// JSONPathOperatorNotExists matches if the property is not present in the event
+	JSONPathOperatorNotExists = "notExists"
+	// JSONPathOperatorGreaterThan matches if the numeric value is greater than the number given in match
+	JSONPathOperatorGreaterThan = "greaterThan"
+	// JSONPathOperatorLessThan matches if the numeric value is less than the number given in match
+	JSONPathOperatorLessThan = "lessThan"
+	// JSONPathOperatorBoolean matches if the value is a boolean that is equal to the boolean given in match
+	JSONPathOperatorBoolean = "boolean"
+)
+
+// IsMatch indicates whether the given event data matches the JSONPath filter
+func (j *JSONPath) IsMatch(jsonEventData interface{}) bool {
+	value, err := jsonpath.Get(j.Property, jsonEventData)
+	exists := err == nil && value != nil
+
+	switch j.Operator {
+	case JSONPathOperatorExists:
+		return exists
+	case JSONPathOperatorNotExists:
+		return !exists
+	}
+
+	if err != nil {
+		return false
+	}
+
+	switch j.Operator {
+	case "", JSONPathOperatorEquals:
+		stringValue, ok := scalarToString(value)
+		return ok && stringValue == j.Match
+	case JSONPathOperatorRegex, JSONPathOperatorGlob:
+		stringValue, ok := scalarToString(value)
+		if !ok {
+			return false
+		}
+		matcher := j.matcher
+		if matcher == nil {
+			if matcher, err = j.compileMatcher(); err != nil {
+				return false
+			}
+		}
+		return matcher.Match(stringValue)
+	case JSONPathOperatorIn:
+		stringValue, ok := scalarToString(value)
+		if !ok {
+			return false
+		}
+		for _, entry := range j.Values {
+			if entry == stringValue {
+				return true
+			}
+		}
+		return false
+	case JSONPathOperatorGreaterThan, JSONPathOperatorLessThan:
+		number, ok := toNumber(value)
+		if !ok {
+			return false
+		}
+		match, err := strconv.ParseFloat(j.Match, 64)
+		if err != nil {
+			return false
+		}
+		if j.Operator == JSONPathOperatorGreaterThan {
+			return number > match
+		}
+		return number < match
+	case JSONPathOperatorBoolean:
+		boolValue, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		match, err := strconv.ParseBool(j.Match)
+		return err == nil && boolValue == match
+	}
+
+	return false
+}
+
+// validate checks if the operator is known and the match or values are valid for the given operator
+func (j *JSONPath) validate() error {
+	switch j.Operator {
+	case "", JSONPathOperatorEquals, JSONPathOperatorExists, JSONPathOperatorNotExists:
+	case JSONPathOperatorRegex, JSONPathOperatorGlob:
+		matcher, err := j.compileMatcher()
+		if err != nil {
+			return err
+		}
+		j.matcher = matcher
+	case JSONPathOperatorIn:
+		if len(j.Values) == 0 {
+			return fmt.Errorf("operator %s for property %s requires a list of values", j.Operator, j.Property)
+		}
+	case JSONPathOperatorGreaterThan, JSONPathOperatorLessThan:
+		if _, err := strconv.ParseFloat(j.Match, 64); err != nil {
+			return fmt.Errorf("operator %s for property %s requires a number: %w", j.Operator, j.Property, err)
+		}
+	case JSONPathOperatorBoolean:
+		if _, err := strconv.ParseBool(j.Match); err != nil {
+			return fmt.Errorf("operator %s for property %s requires a boolean: %w", j.Operator, j.Property, err)
+		}
+	default:
+		return fmt.Errorf("unknown operator %s for property %s", j.Operator, j.Property)
+	}
+
+	if len(j.Values) > 0 && j.Operator != JSONPathOperatorIn {
+		return fmt.Errorf("values can only be used with operator %s", JSONPathOperatorIn)
+	}
+
+	return nil
+}
+
+// compileMatcher compiles the pattern in match for the regex or glob operator. If the filter was not created by
+// NewConfig, IsMatch compiles the pattern on the fly and invalid patterns never match
+func (j *JSONPath) compileMatcher() (stringMatcher, error) {
+	if j.Operator == JSONPathOperatorGlob {
+		pattern, err := glob.Compile(j.Match)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob %s for property %s: %w", j.Match, j.Property, err)
+		}
+		return pattern, nil
+	}
+
+	regex, err := regexp.Compile("^(?:" + j.Match + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %s for property %s: %w", j.Match, j.Property, err)
+	}
+	return regexMatcher{regex: regex}, nil
+}
+
+// scalarToString returns the string representation of strings, numbers and booleans, other types are not supported
+func scalarToString(value interface{}) (string, bool) {
+	switch typedValue := value.(type) {
+	case string:
+		return typedValue, true
+	case bool:
+		return strconv.FormatBool(typedValue), true
+	case float64:
+		return strconv.FormatFloat(typedValue, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(typedValue), true
+	}
+
+	return "", false
+}
+
+// toNumber converts numbers and strings that contain a number to a float64
+func toNumber(value interface{}) (float64, bool) {
+	switch typedValue := value.(type) {
+	case float64:
+		return typedValue, true
+	case int:
+		return float64(typedValue), true
+	case string:
+		number, err := strconv.ParseFloat(typedValue, 64)
+		return number, err == nil
+	}
+
+	return 0, false
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const operatorTestEventData = `
+{
+  "data": {
+    "project": "sockshop",
+    "stage": "dev",
+    "service": "carts",
+    "labels": {
+      "owner": "JohnDoe"
+    },
+    "test": {
+      "teststrategy": "locust",
+      "users": 100,
+      "smoke": true
+    }
+  }
+}`
+
+func TestJSONPathOperators(t *testing.T) {
+	jsonEventData := interface{}(nil)
+	err := json.Unmarshal([]byte(operatorTestEventData), &jsonEventData)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		jsonPath JSONPath
+		expected bool
+	}{
+		{
+			name:     "default operator matches string",
+			jsonPath: JSONPath{Property: "$.data.test.teststrategy", Match: "locust"},
+			expected: true,
+		},
+		{
+			name:     "equals matches number",
+			jsonPath: JSONPath{Property: "$.data.test.users", Operator: JSONPathOperatorEquals, Match: "100"},
+			expected: true,
+		},
+		{
+			name:     "equals matches boolean",
+			jsonPath: JSONPath{Property: "$.data.test.smoke", Match: "true"},
+			expected: true,
+		},
+		{
+			name:     "regex matches",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorRegex, Match: "^(dev|staging)$"},
+			expected: true,
+		},
+		{
+			name:     "regex does not match",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorRegex, Match: "^prod"},
+			expected: false,
+		},
+		{
+			name:     "regex is anchored",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorRegex, Match: "de"},
+			expected: false,
+		},
+		{
+			name:     "glob matches",
+			jsonPath: JSONPath{Property: "$.data.service", Operator: JSONPathOperatorGlob, Match: "car*"},
+			expected: true,
+		},
+		{
+			name:     "in matches",
+			jsonPath: JSONPath{Property: "$.data.service", Operator: JSONPathOperatorIn, Values: []string{"orders", "carts"}},
+			expected: true,
+		},
+		{
+			name:     "in does not match",
+			jsonPath: JSONPath{Property: "$.data.service", Operator: JSONPathOperatorIn, Values: []string{"orders"}},
+			expected: false,
+		},
+		{
+			name:     "exists matches label",
+			jsonPath: JSONPath{Property: "$.data.labels.owner", Operator: JSONPathOperatorExists},
+			expected: true,
+		},
+		{
+			name:     "exists does not match missing label",
+			jsonPath: JSONPath{Property: "$.data.labels.buildId", Operator: JSONPathOperatorExists},
+			expected: false,
+		},
+		{
+			name:     "notExists matches missing label",
+			jsonPath: JSONPath{Property: "$.data.labels.buildId", Operator: JSONPathOperatorNotExists},
+			expected: true,
+		},
+		{
+			name:     "greaterThan matches",
+			jsonPath: JSONPath{Property: "$.data.test.users", Operator: JSONPathOperatorGreaterThan, Match: "50"},
+			expected: true,
+		},
+		{
+			name:     "lessThan does not match",
+			jsonPath: JSONPath{Property: "$.data.test.users", Operator: JSONPathOperatorLessThan, Match: "50"},
+			expected: false,
+		},
+		{
+			name:     "boolean matches",
+			jsonPath: JSONPath{Property: "$.data.test.smoke", Operator: JSONPathOperatorBoolean, Match: "true"},
+			expected: true,
+		},
+		{
+			name:     "boolean does not match string",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorBoolean, Match: "true"},
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.NoError(t, test.jsonPath.validate())
+			assert.Equal(t, test.expected, test.jsonPath.IsMatch(jsonEventData))
+		})
+	}
+}
+
+func TestJSONPathCompiledOnce(t *testing.T) {
+	jsonPath := JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorRegex, Match: "dev|staging"}
+	require.NoError(t, jsonPath.validate())
+	require.NotNil(t, jsonPath.matcher)
+
+	// The compiled pattern is used for matching, even if the pattern itself is changed afterwards
+	jsonPath.Match = "production"
+	assert.True(t, jsonPath.IsMatch(map[string]interface{}{"data": map[string]interface{}{"stage": "staging"}}))
+}
+
+func TestJSONPathValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		jsonPath JSONPath
+	}{
+		{
+			name:     "unknown operator",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: "contains", Match: "dev"},
+		},
+		{
+			name:     "invalid regex",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorRegex, Match: "(dev"},
+		},
+		{
+			name:     "in without values",
+			jsonPath: JSONPath{Property: "$.data.stage", Operator: JSONPathOperatorIn},
+		},
+		{
+			name:     "values without in",
+			jsonPath: JSONPath{Property: "$.data.stage", Values: []string{"dev"}},
+		},
+		{
+			name:     "greaterThan without number",
+			jsonPath: JSONPath{Property: "$.data.test.users", Operator: JSONPathOperatorGreaterThan, Match: "many"},
+		},
+		{
+			name:     "boolean without boolean",
+			jsonPath: JSONPath{Property: "$.data.test.smoke", Operator: JSONPathOperatorBoolean, Match: "yes"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Error(t, test.jsonPath.validate())
+		})
+	}
+}
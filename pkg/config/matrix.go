@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/PaesslerAG/jsonpath"
+)
+
+// Matrix runs a task once for every element of a list. The list is either static or selected from the event by a
+// JSONPath expression, the current element is exposed to the job as environment variable
+type Matrix struct {
+	Env         string   `yaml:"env"`
+	Values      []string `yaml:"values,omitempty"`
+	JSONPath    string   `yaml:"jsonPath,omitempty"`
+	Parallelism int      `yaml:"parallelism,omitempty"`
+}
+
+// GetValues returns the elements of the matrix for the given event. Elements of a list in the event that are maps or
+// lists are converted to JSON, all other elements are formatted as they are
+func (m *Matrix) GetValues(jsonEventData interface{}) ([]string, error) {
+	if m.JSONPath == "" {
+		return m.Values, nil
+	}
+
+	value, err := jsonpath.Get(m.JSONPath, jsonEventData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve matrix %s: %w", m.JSONPath, err)
+	}
+
+	elements, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("matrix %s must select a list, got %v", m.JSONPath, value)
+	}
+
+	values := make([]string, len(elements))
+	for index, element := range elements {
+		kind := reflect.ValueOf(element).Kind()
+		if kind != reflect.Map && kind != reflect.Slice {
+			values[index] = fmt.Sprintf("%v", element)
+			continue
+		}
+
+		jsonElement, err := json.Marshal(element)
+		if err != nil {
+			return nil, fmt.Errorf("unable to convert element %d of matrix %s to JSON: %w", index, m.JSONPath, err)
+		}
+		values[index] = string(jsonElement)
+	}
+
+	return values, nil
+}
+
+// validate checks that the matrix has an environment variable and exactly one source for its elements
+func (m *Matrix) validate() error {
+	if m.Env == "" {
+		return fmt.Errorf("matrix requires env")
+	}
+
+	if !envNamePattern.MatchString(m.Env) {
+		return fmt.Errorf("env %s of matrix must be a valid environment variable name", m.Env)
+	}
+
+	if (len(m.Values) > 0) == (m.JSONPath != "") {
+		return fmt.Errorf("matrix requires either values or jsonPath")
+	}
+
+	if m.JSONPath != "" {
+		if _, err := jsonpath.New(m.JSONPath); err != nil {
+			return fmt.Errorf("invalid jsonPath %s in matrix: %w", m.JSONPath, err)
+		}
+	}
+
+	if m.Parallelism < 0 {
+		return fmt.Errorf("parallelism of matrix must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatrix(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "UI tests"
+        image: "cypress/included"
+        matrix:
+          env: BROWSER
+          values: ["chrome", "firefox"]
+          parallelism: 1
+      - name: "Smoke tests"
+        image: "curlimages/curl"
+        matrix:
+          env: ENDPOINT
+          jsonPath: "$.data.endpoints"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Run tests")
+	require.True(t, found)
+
+	eventData := map[string]interface{}{
+		"data": map[string]interface{}{
+			"endpoints": []interface{}{"http://carts", 8080, map[string]interface{}{"url": "http://orders"}},
+		},
+	}
+
+	found, uiTests := action.FindTaskByName("UI tests")
+	require.True(t, found)
+	assert.Equal(t, &Matrix{Env: "BROWSER", Values: []string{"chrome", "firefox"}, Parallelism: 1}, uiTests.Matrix)
+
+	values, err := uiTests.Matrix.GetValues(eventData)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chrome", "firefox"}, values)
+
+	found, smokeTests := action.FindTaskByName("Smoke tests")
+	require.True(t, found)
+
+	values, err = smokeTests.Matrix.GetValues(eventData)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http://carts", "8080", `{"url":"http://orders"}`}, values)
+
+	_, err = smokeTests.Matrix.GetValues(map[string]interface{}{"data": map[string]interface{}{"endpoints": "http://carts"}})
+	assert.ErrorContains(t, err, "matrix $.data.endpoints must select a list")
+
+	_, err = smokeTests.Matrix.GetValues(map[string]interface{}{})
+	assert.ErrorContains(t, err, "unable to resolve matrix $.data.endpoints")
+}
+
+func TestInvalidMatrix(t *testing.T) {
+	tests := []struct {
+		name          string
+		matrixYaml    string
+		expectedError string
+	}{
+		{
+			name: "missing env",
+			matrixYaml: `
+          values: ["chrome"]`,
+			expectedError: "matrix requires env",
+		},
+		{
+			name: "invalid env name",
+			matrixYaml: `
+          env: my-var
+          values: ["chrome"]`,
+			expectedError: "env my-var of matrix must be a valid environment variable name",
+		},
+		{
+			name: "missing values",
+			matrixYaml: `
+          env: BROWSER`,
+			expectedError: "matrix requires either values or jsonPath",
+		},
+		{
+			name: "values and jsonPath",
+			matrixYaml: `
+          env: BROWSER
+          values: ["chrome"]
+          jsonPath: "$.data.browsers"`,
+			expectedError: "matrix requires either values or jsonPath",
+		},
+		{
+			name: "invalid jsonPath",
+			matrixYaml: `
+          env: BROWSER
+          jsonPath: "$.data.browsers["`,
+			expectedError: "invalid jsonPath $.data.browsers[ in matrix",
+		},
+		{
+			name: "negative parallelism",
+			matrixYaml: `
+          env: BROWSER
+          values: ["chrome"]
+          parallelism: -1`,
+			expectedError: "parallelism of matrix must not be negative",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "UI tests"
+        image: "cypress/included"
+        matrix:` + test.matrixYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task UI tests in action Run tests: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
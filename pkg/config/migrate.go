@@ -0,0 +1,154 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// eventFilterKeys are the keys of a v2 event that are combined into the condition of a v3 event
+var eventFilterKeys = []string{"jsonpath", "all", "any", "not"}
+
+// MigrateV2ToV3 rewrites the content of a v2 job configuration to the v3 schema. The content is modified on the
+// level of the YAML document, such that comments and the formatting of values are preserved where possible
+func MigrateV2ToV3(yamlContent []byte) ([]byte, error) {
+	var document yamlv3.Node
+	if err := yamlv3.Unmarshal(yamlContent, &document); err != nil {
+		return nil, fmt.Errorf("unable to parse job config: %w", err)
+	}
+
+	if document.Kind != yamlv3.DocumentNode || len(document.Content) != 1 ||
+		document.Content[0].Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("job config must be a YAML mapping")
+	}
+	root := document.Content[0]
+
+	apiVersion := mappingValue(root, "apiVersion")
+	if apiVersion == nil || apiVersion.Value != APIVersionV2 {
+		return nil, fmt.Errorf("only job configs with apiVersion %s can be migrated to %s", APIVersionV2, APIVersionV3)
+	}
+	apiVersion.Value = APIVersionV3
+
+	if actions := mappingValue(root, "actions"); actions != nil && actions.Kind == yamlv3.SequenceNode {
+		for _, action := range actions.Content {
+			events := mappingValue(action, "events")
+			if events == nil || events.Kind != yamlv3.SequenceNode {
+				continue
+			}
+
+			for _, event := range events.Content {
+				if event.Kind == yamlv3.MappingNode {
+					migrateEventToV3(event)
+				}
+			}
+		}
+	}
+
+	var migratedContent bytes.Buffer
+	encoder := yamlv3.NewEncoder(&migratedContent)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&document); err != nil {
+		return nil, fmt.Errorf("unable to write migrated job config: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("unable to write migrated job config: %w", err)
+	}
+
+	return migratedContent.Bytes(), nil
+}
+
+// migrateEventToV3 combines the filters of the event into a single condition. Names of v2 events without a match mode
+// are regular expressions that only have to match a part of the event type, while v3 matches event names exactly by
+// default. Such names are therefore wrapped into a regex that matches the same event types
+func migrateEventToV3(event *yamlv3.Node) {
+	name := mappingValue(event, "name")
+	if name != nil && mappingValue(event, "matchMode") == nil {
+		name.Value = ".*(?:" + name.Value + ").*"
+		insertMappingEntry(event, indexOfMappingKey(event, "name")+2, "matchMode", EventMatchModeRegex)
+	}
+
+	var filters []*yamlv3.Node
+	for _, filterKey := range eventFilterKeys {
+		keyIndex := indexOfMappingKey(event, filterKey)
+		if keyIndex < 0 {
+			continue
+		}
+
+		filters = append(filters, event.Content[keyIndex], event.Content[keyIndex+1])
+		event.Content = append(event.Content[:keyIndex], event.Content[keyIndex+2:]...)
+	}
+
+	if len(filters) == 0 {
+		return
+	}
+
+	condition := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+
+	if len(filters) == 2 {
+		// A single filter can be used as condition directly
+		condition.Content = filters
+	} else {
+		// Multiple filters are combined with all, the entries of an existing all filter are added directly
+		allConditions := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		for index := 0; index < len(filters); index += 2 {
+			if filters[index].Value == "all" && filters[index+1].Kind == yamlv3.SequenceNode {
+				allConditions.Content = append(allConditions.Content, filters[index+1].Content...)
+				continue
+			}
+
+			allConditions.Content = append(allConditions.Content, &yamlv3.Node{
+				Kind:    yamlv3.MappingNode,
+				Tag:     "!!map",
+				Content: []*yamlv3.Node{filters[index], filters[index+1]},
+			})
+		}
+
+		condition.Content = []*yamlv3.Node{
+			{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "all"},
+			allConditions,
+		}
+	}
+
+	event.Content = append(event.Content,
+		&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "condition"},
+		condition,
+	)
+}
+
+// indexOfMappingKey returns the index of the key node in the content of the mapping or -1 if the key is not present
+func indexOfMappingKey(mapping *yamlv3.Node, key string) int {
+	if mapping.Kind != yamlv3.MappingNode {
+		return -1
+	}
+
+	for index := 0; index+1 < len(mapping.Content); index += 2 {
+		if mapping.Content[index].Value == key {
+			return index
+		}
+	}
+
+	return -1
+}
+
+// mappingValue returns the value node of the given key in the mapping or nil if the key is not present
+func mappingValue(mapping *yamlv3.Node, key string) *yamlv3.Node {
+	index := indexOfMappingKey(mapping, key)
+	if index < 0 {
+		return nil
+	}
+
+	return mapping.Content[index+1]
+}
+
+// insertMappingEntry inserts a new key with a string value at the given index into the content of the mapping
+func insertMappingEntry(mapping *yamlv3.Node, index int, key string, value string) {
+	entry := []*yamlv3.Node{
+		{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key},
+		{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value},
+	}
+
+	mapping.Content = append(mapping.Content[:index], append(entry, mapping.Content[index:]...)...)
+}
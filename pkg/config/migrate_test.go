@@ -0,0 +1,186 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateV2ToV3(t *testing.T) {
+	v2Config := `# Job configuration of the carts service
+apiVersion: v2
+actions:
+  - name: "Run locust"
+    events:
+      # Only run locust tests outside of production
+      - name: "sh.keptn.event.test.triggered"
+        jsonpath:
+          property: "$.data.test.teststrategy" # set by the shipyard
+          match: "locust"
+        not:
+          jsonpath:
+            property: "$.data.stage"
+            match: "production"
+      - name: "sh.keptn.event.*.finished"
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+        args:
+          - '-f'
+`
+
+	expectedV3Config := `# Job configuration of the carts service
+apiVersion: v3
+actions:
+  - name: "Run locust"
+    events:
+      # Only run locust tests outside of production
+      - name: ".*(?:sh.keptn.event.test.triggered).*"
+        matchMode: regex
+        condition:
+          all:
+            - jsonpath:
+                property: "$.data.test.teststrategy" # set by the shipyard
+                match: "locust"
+            - not:
+                jsonpath:
+                  property: "$.data.stage"
+                  match: "production"
+      - name: ".*(?:sh.keptn.event.*.finished).*"
+        matchMode: regex
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+        args:
+          - '-f'
+`
+
+	migratedConfig, err := MigrateV2ToV3([]byte(v2Config))
+	require.NoError(t, err)
+	assert.Equal(t, expectedV3Config, string(migratedConfig))
+
+	v2, err := NewConfig([]byte(v2Config))
+	require.NoError(t, err)
+	v3, err := NewConfig(migratedConfig)
+	require.NoError(t, err)
+
+	matchingEvent := map[string]interface{}{
+		"data": map[string]interface{}{"stage": "dev", "test": map[string]interface{}{"teststrategy": "locust"}},
+	}
+	productionEvent := map[string]interface{}{
+		"data": map[string]interface{}{"stage": "production", "test": map[string]interface{}{"teststrategy": "locust"}},
+	}
+
+	for _, config := range []*Config{v2, v3} {
+		assert.True(t, config.IsEventMatch("sh.keptn.event.test.triggered", matchingEvent))
+		assert.False(t, config.IsEventMatch("sh.keptn.event.test.triggered", productionEvent))
+		assert.True(t, config.IsEventMatch("sh.keptn.event.deployment.finished", nil))
+	}
+}
+
+func TestMigrateV2ToV3SingleFilter(t *testing.T) {
+	v2Config := `apiVersion: v2
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+        jsonpath:
+          property: "$.data.test.teststrategy"
+          match: "locust"
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+`
+
+	expectedV3Config := `apiVersion: v3
+actions:
+  - name: "Run locust"
+    events:
+      - name: ".*(?:sh.keptn.event.test.triggered).*"
+        matchMode: regex
+        condition:
+          jsonpath:
+            property: "$.data.test.teststrategy"
+            match: "locust"
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+`
+
+	migratedConfig, err := MigrateV2ToV3([]byte(v2Config))
+	require.NoError(t, err)
+	assert.Equal(t, expectedV3Config, string(migratedConfig))
+}
+
+func TestMigrateV2ToV3RegexEventName(t *testing.T) {
+	v2Config := `apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.(test|deployment).triggered"
+    tasks:
+      - name: "Run tests"
+        image: "alpine"
+`
+
+	expectedV3Config := `apiVersion: v3
+actions:
+  - name: "Run tests"
+    events:
+      - name: ".*(?:sh.keptn.event.(test|deployment).triggered).*"
+        matchMode: regex
+    tasks:
+      - name: "Run tests"
+        image: "alpine"
+`
+
+	migratedConfig, err := MigrateV2ToV3([]byte(v2Config))
+	require.NoError(t, err)
+	assert.Equal(t, expectedV3Config, string(migratedConfig))
+
+	v3, err := NewConfig(migratedConfig)
+	require.NoError(t, err)
+	assert.True(t, v3.IsEventMatch("sh.keptn.event.deployment.triggered", nil))
+}
+
+func TestMigrateV2ToV3KeepsMatchingEvents(t *testing.T) {
+	v2Config := `apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test"
+      - name: "sh.keptn.event.deployment.finished"
+        matchMode: exact
+    tasks:
+      - name: "Run tests"
+        image: "alpine"
+`
+
+	migratedConfig, err := MigrateV2ToV3([]byte(v2Config))
+	require.NoError(t, err)
+
+	v2, err := NewConfig([]byte(v2Config))
+	require.NoError(t, err)
+	v3, err := NewConfig(migratedConfig)
+	require.NoError(t, err)
+
+	for _, eventType := range []string{
+		"sh.keptn.event.test",
+		"sh.keptn.event.test.triggered",
+		"sh.keptn.event.testing.finished",
+		"shXkeptnXeventXtest.triggered",
+		"sh.keptn.event.deployment.finished",
+		"sh.keptn.event.deployment.finished.foo",
+		"sh.keptn.event.evaluation.triggered",
+	} {
+		assert.Equal(t, v2.IsEventMatch(eventType, nil), v3.IsEventMatch(eventType, nil), eventType)
+	}
+	assert.True(t, v3.IsEventMatch("sh.keptn.event.test.triggered", nil))
+	assert.False(t, v3.IsEventMatch("sh.keptn.event.deployment.finished.foo", nil))
+}
+
+func TestMigrateRejectsOtherVersions(t *testing.T) {
+	_, err := MigrateV2ToV3([]byte("apiVersion: v3\nactions: []\n"))
+	assert.Error(t, err)
+}
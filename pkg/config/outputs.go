@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OutputsFilePath is the file in the job container the outputs of a task are written to
+const OutputsFilePath = "/keptn/outputs"
+
+// envNamePattern matches valid environment variable names, which are required for outputs and the env of a matrix
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseOutputs reads the declared outputs of the task from the content of the outputs file, which contains one
+// key=value pair per line. Lines that don't contain a declared output are ignored
+func (t *Task) ParseOutputs(content string) map[string]string {
+	declared := make(map[string]bool, len(t.Outputs))
+	for _, name := range t.Outputs {
+		declared[name] = true
+	}
+
+	outputs := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		name, value, found := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		name = strings.TrimSpace(name)
+		if !found || !declared[name] {
+			continue
+		}
+
+		outputs[name] = value
+	}
+
+	return outputs
+}
+
+// validateOutputs checks that the outputs of the task are unique and valid environment variable names
+func (t *Task) validateOutputs() error {
+	names := make(map[string]bool, len(t.Outputs))
+	for _, name := range t.Outputs {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("output %s must be a valid environment variable name", name)
+		}
+
+		if names[name] {
+			return fmt.Errorf("output %s is declared twice", name)
+		}
+		names[name] = true
+	}
+
+	return nil
+}
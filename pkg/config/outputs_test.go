@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputs(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Build"
+        image: "alpine"
+        outputs: ["IMAGE_TAG", "IMAGE_DIGEST"]
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Build and deploy")
+	require.True(t, found)
+
+	found, build := action.FindTaskByName("Build")
+	require.True(t, found)
+
+	outputs := build.ParseOutputs("IMAGE_TAG=1.2.3\r\nIMAGE_DIGEST=sha256:abc=\nUNDECLARED=value\ninvalid line\n\n")
+	assert.Equal(t, map[string]string{"IMAGE_TAG": "1.2.3", "IMAGE_DIGEST": "sha256:abc="}, outputs)
+
+	assert.Empty(t, build.ParseOutputs(""))
+}
+
+func TestInvalidOutputs(t *testing.T) {
+	tests := []struct {
+		name          string
+		outputs       string
+		expectedError string
+	}{
+		{
+			name:          "invalid name",
+			outputs:       `["IMAGE-TAG"]`,
+			expectedError: "output IMAGE-TAG must be a valid environment variable name",
+		},
+		{
+			name:          "duplicate name",
+			outputs:       `["IMAGE_TAG", "IMAGE_TAG"]`,
+			expectedError: "output IMAGE_TAG is declared twice",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Build"
+        image: "alpine"
+        outputs: ` + test.outputs
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Build in action Build and deploy: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
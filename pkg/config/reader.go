@@ -3,7 +3,9 @@ package config
 import (
 	"fmt"
 	"golang.org/x/crypto/sha3"
+	"hash"
 	"log"
+	"strings"
 )
 
 // Needs to be escaped manually when sending it to the api-gateway-nginx
@@ -35,45 +37,144 @@ type JobConfigReader struct {
 // FindJobConfigResource searches for the job configuration resource in the service, stage and then the project
 // and returns the content of the first resource that is found
 func (jcr *JobConfigReader) FindJobConfigResource(gitCommitID string) ([]byte, error) {
-	if config, err := jcr.Keptn.GetServiceResource(jobConfigResourceName, gitCommitID); err == nil {
-		return config, nil
+	config, err := jcr.findResource(jobConfigResourceName, gitCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find job configuration")
+	}
+
+	return config, nil
+}
+
+// findResource searches for the given resource in the service, stage and then the project and returns the content
+// of the first resource that is found
+func (jcr *JobConfigReader) findResource(resourceName string, gitCommitID string) ([]byte, error) {
+	if resource, err := jcr.Keptn.GetServiceResource(resourceName, gitCommitID); err == nil {
+		return resource, nil
 	}
 
-	if config, err := jcr.Keptn.GetStageResource(jobConfigResourceName, gitCommitID); err == nil {
-		return config, nil
+	if resource, err := jcr.Keptn.GetStageResource(resourceName, gitCommitID); err == nil {
+		return resource, nil
 	}
 
 	// NOTE: Since the resource service uses different branches, the commitID may not be in the main
 	//       branch and therefore it's not possible to query the project fallback configuration!
-	if config, err := jcr.Keptn.GetProjectResource(jobConfigResourceName, ""); err == nil {
-		return config, nil
+	if resource, err := jcr.Keptn.GetProjectResource(resourceName, ""); err == nil {
+		return resource, nil
+	}
+
+	return nil, fmt.Errorf("unable to find resource %s", resourceName)
+}
+
+// ResolveIncludes returns a configuration where the include directives of the given configuration are replaced by
+// the actions of the included resources. The included resources are searched in the service, stage and then the
+// project. Actions of the configuration replace included actions with the same name, see Config.Merge
+func (jcr *JobConfigReader) ResolveIncludes(configuration *Config, gitCommitID string) (*Config, error) {
+	return jcr.resolveIncludes(configuration, gitCommitID, []string{jobConfigResourceName}, nil)
+}
+
+// resolveIncludes recursively resolves the includes of the configuration, includeChain contains the resources that
+// are currently resolved and is used to detect cycles. The content of all included resources is written to the
+// hasher if one is given
+func (jcr *JobConfigReader) resolveIncludes(
+	configuration *Config, gitCommitID string, includeChain []string, hasher hash.Hash,
+) (*Config, error) {
+	if len(configuration.Include) == 0 {
+		return configuration, nil
 	}
 
-	return nil, fmt.Errorf("unable to find job configuration")
+	var includedConfig *Config
+	for _, include := range configuration.Include {
+		for _, resourceName := range includeChain {
+			if resourceName == include {
+				return nil, fmt.Errorf("include cycle detected: %s -> %s", strings.Join(includeChain, " -> "), include)
+			}
+		}
+
+		resource, err := jcr.findResource(include, gitCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to include %s: %w", include, err)
+		}
+
+		if hasher != nil {
+			hasher.Write(resource)
+		}
+
+		parsedInclude, err := NewConfig(resource)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing included configuration %s: %w", include, err)
+		}
+
+		nestedIncludeChain := append(append([]string{}, includeChain...), include)
+		resolvedInclude, err := jcr.resolveIncludes(parsedInclude, gitCommitID, nestedIncludeChain, hasher)
+		if err != nil {
+			return nil, err
+		}
+
+		if includedConfig == nil {
+			includedConfig = resolvedInclude
+		} else {
+			includedConfig = includedConfig.Merge(resolvedInclude)
+		}
+	}
+
+	resolvedConfig := includedConfig.Merge(configuration)
+	resolvedConfig.Inherit = configuration.Inherit
+
+	return resolvedConfig, nil
 }
 
 // GetJobConfig retrieves job/config.yaml resource from keptn and parses it into a Config struct.
-// Additionally, also the SHA1 hash of the retrieved configuration will be returned.
+// If the configuration has set inherit to true, the configurations of the next levels (stage and project) are
+// retrieved as well and merged into one configuration, see ResolveInheritance for details.
+// Additionally, also the SHA1 hash of the retrieved configurations will be returned.
 // In case of error retrieving the resource or parsing the yaml it will return (nil,
 // error) with the original error correctly wrapped in the local one
 func (jcr *JobConfigReader) GetJobConfig(gitCommitID string) (*Config, string, error) {
 
-	resource, err := jcr.FindJobConfigResource(gitCommitID)
-	if err != nil {
-		return nil, "", fmt.Errorf("error retrieving job config: %w", err)
+	configLevels := []func() ([]byte, error){
+		func() ([]byte, error) { return jcr.Keptn.GetServiceResource(jobConfigResourceName, gitCommitID) },
+		func() ([]byte, error) { return jcr.Keptn.GetStageResource(jobConfigResourceName, gitCommitID) },
+		// NOTE: Since the resource service uses different branches, the commitID may not be in the main
+		//       branch and therefore it's not possible to query the project fallback configuration!
+		func() ([]byte, error) { return jcr.Keptn.GetProjectResource(jobConfigResourceName, "") },
 	}
 
 	hasher := sha3.New224()
-	hasher.Write(resource)
-	resourceHashBytes := hasher.Sum(nil)
-	resourceHash := fmt.Sprintf("%x", resourceHashBytes)
+	var configurations []*Config
+
+	for _, getResource := range configLevels {
+		resource, err := getResource()
+		if err != nil {
+			continue
+		}
+
+		hasher.Write(resource)
+
+		configuration, err := NewConfig(resource)
+		if err != nil {
+			log.Printf("Could not parse config: %s", err)
+			log.Printf("The config was: %s", string(resource))
+			return nil, "", fmt.Errorf("error parsing job configuration: %w", err)
+		}
+
+		configuration, err = jcr.resolveIncludes(configuration, gitCommitID, []string{jobConfigResourceName}, hasher)
+		if err != nil {
+			return nil, "", fmt.Errorf("error resolving includes of job configuration: %w", err)
+		}
+
+		configurations = append(configurations, configuration)
+
+		// Only continue with the next level if the configuration inherits from it
+		if !configuration.Inherit {
+			break
+		}
+	}
 
-	configuration, err := NewConfig(resource)
-	if err != nil {
-		log.Printf("Could not parse config: %s", err)
-		log.Printf("The config was: %s", string(resource))
-		return nil, "", fmt.Errorf("error parsing job configuration: %w", err)
+	if len(configurations) == 0 {
+		return nil, "", fmt.Errorf("error retrieving job config: unable to find job configuration")
 	}
 
-	return configuration, resourceHash, nil
+	resourceHash := fmt.Sprintf("%x", hasher.Sum(nil))
+
+	return ResolveInheritance(configurations), resourceHash, nil
 }
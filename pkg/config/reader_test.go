@@ -3,10 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"keptn-contrib/job-executor-service/pkg/config/fake"
 )
@@ -173,3 +175,201 @@ func TestJobConfigReader_FindJobConfigResource(t *testing.T) {
 	})
 
 }
+
+func TestGetJobConfigWithInheritance(t *testing.T) {
+	serviceConfig := `
+apiVersion: v2
+inherit: true
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run service tests"
+        image: "alpine"
+  - name: "Notify"
+    events:
+      - name: "sh.keptn.event.deployment.finished"
+    tasks:
+      - name: "Send notification"
+        image: "curlimages/curl"
+`
+
+	stageConfig := `
+apiVersion: v2
+inherit: true
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run stage tests"
+        image: "alpine"
+`
+
+	projectConfig := `
+apiVersion: v2
+actions:
+  - name: "Deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Run helm"
+        image: "alpine/helm"
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run project tests"
+        image: "alpine"
+`
+
+	t.Run("Merge all levels", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return([]byte(serviceConfig), nil)
+		mockKeptnResourceService.EXPECT().GetStageResource("job/config.yaml", "").Return([]byte(stageConfig), nil)
+		mockKeptnResourceService.EXPECT().GetProjectResource("job/config.yaml", "").Return([]byte(projectConfig), nil)
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		require.NoError(t, err)
+		require.Len(t, config.Actions, 3)
+
+		assert.Equal(t, "Deploy", config.Actions[0].Name)
+		assert.Equal(t, "Run tests", config.Actions[1].Name)
+		assert.Equal(t, "Run service tests", config.Actions[1].Tasks[0].Name)
+		assert.Equal(t, "Notify", config.Actions[2].Name)
+	})
+
+	t.Run("Skip missing stage", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return([]byte(serviceConfig), nil)
+		mockKeptnResourceService.EXPECT().GetStageResource("job/config.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetProjectResource("job/config.yaml", "").Return([]byte(projectConfig), nil)
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		require.NoError(t, err)
+		require.Len(t, config.Actions, 3)
+		assert.Equal(t, "Run service tests", config.Actions[1].Tasks[0].Name)
+	})
+
+	t.Run("Stop at config without inherit", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetStageResource("job/config.yaml", "").Return(
+			[]byte(strings.Replace(stageConfig, "inherit: true", "inherit: false", 1)), nil,
+		)
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		require.NoError(t, err)
+		require.Len(t, config.Actions, 1)
+		assert.Equal(t, "Run stage tests", config.Actions[0].Tasks[0].Name)
+	})
+}
+
+func TestGetJobConfigWithIncludes(t *testing.T) {
+	serviceConfig := `
+apiVersion: v2
+include:
+  - job/common/locust.yaml
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run service tests"
+        image: "alpine"
+`
+
+	locustConfig := `
+apiVersion: v2
+include:
+  - job/common/base.yaml
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run locust tests"
+        image: "locustio/locust"
+`
+
+	baseConfig := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run base tests"
+        image: "alpine"
+`
+
+	t.Run("Resolve nested includes", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return([]byte(serviceConfig), nil)
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/common/locust.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetStageResource("job/common/locust.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetProjectResource("job/common/locust.yaml", "").Return([]byte(locustConfig), nil)
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/common/base.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetStageResource("job/common/base.yaml", "").Return([]byte(baseConfig), nil)
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		require.NoError(t, err)
+		require.Len(t, config.Actions, 2)
+
+		assert.Equal(t, "Run tests", config.Actions[0].Name)
+		assert.Equal(t, "Run service tests", config.Actions[0].Tasks[0].Name)
+		assert.Equal(t, "Run locust", config.Actions[1].Name)
+		assert.Empty(t, config.Include)
+	})
+
+	t.Run("Detect include cycle", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		cyclicBaseConfig := strings.Replace(baseConfig, "apiVersion: v2", "apiVersion: v2\ninclude:\n  - job/common/locust.yaml", 1)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return([]byte(serviceConfig), nil)
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/common/locust.yaml", "").Return([]byte(locustConfig), nil)
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/common/base.yaml", "").Return([]byte(cyclicBaseConfig), nil)
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "include cycle detected")
+		assert.Nil(t, config)
+	})
+
+	t.Run("Missing include", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		mockKeptnResourceService := fake.NewMockKeptnResourceService(mockCtrl)
+
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/config.yaml", "").Return([]byte(serviceConfig), nil)
+		mockKeptnResourceService.EXPECT().GetServiceResource("job/common/locust.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetStageResource("job/common/locust.yaml", "").Return(nil, fmt.Errorf("not found"))
+		mockKeptnResourceService.EXPECT().GetProjectResource("job/common/locust.yaml", "").Return(nil, fmt.Errorf("not found"))
+
+		sut := JobConfigReader{Keptn: mockKeptnResourceService}
+
+		config, _, err := sut.GetJobConfig("")
+		assert.Error(t, err)
+		assert.Nil(t, config)
+	})
+}
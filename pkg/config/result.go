@@ -0,0 +1,88 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// ResultFilePath is the file in the job container a task writes the data to that is published in the finished event
+const ResultFilePath = "/keptn/result.json"
+
+// reservedResultProperties contains the properties of the finished event that are set by the job-executor-service and
+// can't be published by a task
+var reservedResultProperties = []string{"project", "stage", "service", "status", "result", "message"}
+
+// TerminationMessagePath returns the file in the job container that is passed to the job-executor-service as
+// termination message, which is either the outputs file or the result file. An empty path is returned if the task
+// neither declares outputs nor publishes a result
+func (t *Task) TerminationMessagePath() string {
+	if len(t.Outputs) > 0 {
+		return OutputsFilePath
+	}
+
+	if t.PublishResult {
+		return ResultFilePath
+	}
+
+	return ""
+}
+
+// ParseResult parses the content of the result file, which has to contain a JSON object that doesn't set one of the
+// reserved properties of the finished event. The property named after the Keptn task of the event, e.g. test for a
+// test.finished event, is reserved as well, unless the task name is empty. An empty result file publishes no data
+func ParseResult(content string, eventTaskName string) (map[string]interface{}, error) {
+	if content == "" {
+		return nil, nil
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("result must be a JSON object: %w", err)
+	}
+
+	reservedProperties := reservedResultProperties
+	if eventTaskName != "" {
+		reservedProperties = append([]string{eventTaskName}, reservedResultProperties...)
+	}
+
+	for _, property := range reservedProperties {
+		if _, ok := result[property]; ok {
+			return nil, fmt.Errorf("result must not contain the reserved property %s", property)
+		}
+	}
+
+	return result, nil
+}
+
+// MergeResults merges the data of the result into the data of the finished event. Objects are merged recursively, all
+// other values of the result replace the values of the event data
+func MergeResults(eventData map[string]interface{}, result map[string]interface{}) {
+	// The properties are sorted to merge deterministically
+	properties := make([]string, 0, len(result))
+	for property := range result {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+
+	for _, property := range properties {
+		resultObject, isResultObject := result[property].(map[string]interface{})
+		eventObject, isEventObject := eventData[property].(map[string]interface{})
+		if isResultObject && isEventObject {
+			MergeResults(eventObject, resultObject)
+			continue
+		}
+
+		eventData[property] = result[property]
+	}
+}
+
+// validatePublishResult checks that the task doesn't publish a result and declare outputs at the same time, since both
+// are passed as termination message of the job container
+func (t *Task) validatePublishResult() error {
+	if t.PublishResult && len(t.Outputs) > 0 {
+		return fmt.Errorf("publishResult can't be used together with outputs")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResult(t *testing.T) {
+	result, err := ParseResult(`{"deploymentURI": "http://carts.sockshop", "report": {"url": "https://reports/4711"}}`, "test")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"deploymentURI": "http://carts.sockshop",
+		"report":        map[string]interface{}{"url": "https://reports/4711"},
+	}, result)
+
+	result, err = ParseResult("", "test")
+	require.NoError(t, err)
+	assert.Nil(t, result)
+
+	_, err = ParseResult(`["not", "an", "object"]`, "test")
+	assert.ErrorContains(t, err, "result must be a JSON object")
+
+	for _, property := range []string{"project", "stage", "service", "status", "result", "message"} {
+		_, err = ParseResult(`{"`+property+`": "other"}`, "test")
+		assert.ErrorContains(t, err, "result must not contain the reserved property "+property)
+	}
+
+	// The payload of the Keptn task of the event is reserved, other task payloads can be published
+	_, err = ParseResult(`{"test": {"start": "2022-01-01T00:00:00Z"}}`, "test")
+	assert.ErrorContains(t, err, "result must not contain the reserved property test")
+
+	result, err = ParseResult(`{"test": {"reportURL": "https://reports/4711"}}`, "deployment")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"test": map[string]interface{}{"reportURL": "https://reports/4711"}}, result)
+}
+
+func TestMergeResults(t *testing.T) {
+	eventData := map[string]interface{}{
+		"project": "sockshop",
+		"test":    map[string]interface{}{"start": "2022-01-01T00:00:00Z"},
+	}
+
+	MergeResults(eventData, map[string]interface{}{
+		"test":            map[string]interface{}{"reportURL": "https://reports/4711"},
+		"artifactVersion": "1.2.3",
+	})
+	MergeResults(eventData, nil)
+
+	assert.Equal(t, map[string]interface{}{
+		"project":         "sockshop",
+		"test":            map[string]interface{}{"start": "2022-01-01T00:00:00Z", "reportURL": "https://reports/4711"},
+		"artifactVersion": "1.2.3",
+	}, eventData)
+}
+
+func TestTerminationMessagePath(t *testing.T) {
+	assert.Equal(t, "", (&Task{}).TerminationMessagePath())
+	assert.Equal(t, "/keptn/outputs", (&Task{Outputs: []string{"IMAGE_TAG"}}).TerminationMessagePath())
+	assert.Equal(t, "/keptn/result.json", (&Task{PublishResult: true}).TerminationMessagePath())
+}
+
+func TestInvalidPublishResult(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Build"
+        image: "alpine"
+        outputs: ["IMAGE_TAG"]
+        publishResult: true
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.ErrorContains(t, err, "invalid task Build in action Build and deploy: publishResult can't be used together with outputs")
+	assert.Nil(t, config)
+}
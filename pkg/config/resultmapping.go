@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// ResultPass maps an exit code to a passed task
+	ResultPass = "pass"
+	// ResultWarning maps an exit code to a task that passed with a warning
+	ResultWarning = "warning"
+	// ResultFail maps an exit code to a failed task
+	ResultFail = "fail"
+)
+
+// GetResultForExitCode returns the result of the task for the exit code of its job container. Exit codes that are not
+// contained in the result mapping pass if they are 0 and fail otherwise
+func (t *Task) GetResultForExitCode(exitCode int32) string {
+	if result, ok := t.ResultMapping[int(exitCode)]; ok {
+		return result
+	}
+
+	if exitCode == 0 {
+		return ResultPass
+	}
+
+	return ResultFail
+}
+
+// validateResultMapping checks that the result mapping only contains valid exit codes and results
+func (t *Task) validateResultMapping() error {
+	// The exit codes are sorted to report errors deterministically
+	exitCodes := make([]int, 0, len(t.ResultMapping))
+	for exitCode := range t.ResultMapping {
+		exitCodes = append(exitCodes, exitCode)
+	}
+	sort.Ints(exitCodes)
+
+	for _, exitCode := range exitCodes {
+		if exitCode < 0 || exitCode > 255 {
+			return fmt.Errorf("exit code %d in resultMapping must be between 0 and 255", exitCode)
+		}
+
+		switch t.ResultMapping[exitCode] {
+		case ResultPass, ResultWarning, ResultFail:
+		default:
+			return fmt.Errorf("unknown result %s for exit code %d in resultMapping, must be one of %s, %s or %s",
+				t.ResultMapping[exitCode], exitCode, ResultPass, ResultWarning, ResultFail)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultMapping(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Lint"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run linter"
+        image: "golangci/golangci-lint"
+        resultMapping:
+          2: warning
+          3: pass
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Lint")
+	require.True(t, found)
+
+	found, task := action.FindTaskByName("Run linter")
+	require.True(t, found)
+
+	assert.Equal(t, ResultPass, task.GetResultForExitCode(0))
+	assert.Equal(t, ResultFail, task.GetResultForExitCode(1))
+	assert.Equal(t, ResultWarning, task.GetResultForExitCode(2))
+	assert.Equal(t, ResultPass, task.GetResultForExitCode(3))
+	assert.Equal(t, ResultFail, task.GetResultForExitCode(137))
+
+	assert.Equal(t, ResultFail, (&Task{ResultMapping: map[int]string{0: ResultFail}}).GetResultForExitCode(0))
+}
+
+func TestInvalidResultMapping(t *testing.T) {
+	tests := []struct {
+		name          string
+		resultMapping string
+		expectedError string
+	}{
+		{
+			name:          "unknown result",
+			resultMapping: `{2: warn}`,
+			expectedError: "unknown result warn for exit code 2 in resultMapping, must be one of pass, warning or fail",
+		},
+		{
+			name:          "invalid exit code",
+			resultMapping: `{256: warning}`,
+			expectedError: "exit code 256 in resultMapping must be between 0 and 255",
+		},
+		{
+			name:          "exit code is no number",
+			resultMapping: `{two: warning}`,
+			expectedError: "cannot unmarshal",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Lint"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run linter"
+        image: "golangci/golangci-lint"
+        resultMapping: ` + test.resultMapping
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
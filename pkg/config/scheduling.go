@@ -0,0 +1,152 @@
+package config
+
+import "fmt"
+
+// Toleration of the pod of the job, it mirrors the Toleration which is provided by Kubernetes
+type Toleration struct {
+	Key               string `yaml:"key,omitempty"`
+	Operator          string `yaml:"operator,omitempty"`
+	Value             string `yaml:"value,omitempty"`
+	Effect            string `yaml:"effect,omitempty"`
+	TolerationSeconds *int64 `yaml:"tolerationSeconds,omitempty"`
+}
+
+// Affinity of the pod of the job, it's a subset of the Affinity which is provided by Kubernetes that only contains the
+// node affinity
+type Affinity struct {
+	NodeAffinity *NodeAffinity `yaml:"nodeAffinity,omitempty"`
+}
+
+// NodeAffinity contains the node selector terms a node must match and the terms a node should preferably match
+type NodeAffinity struct {
+	RequiredDuringSchedulingIgnoredDuringExecution  *NodeSelector             `yaml:"requiredDuringSchedulingIgnoredDuringExecution,omitempty"`
+	PreferredDuringSchedulingIgnoredDuringExecution []PreferredSchedulingTerm `yaml:"preferredDuringSchedulingIgnoredDuringExecution,omitempty"`
+}
+
+// NodeSelector is matched by a node if the node matches any of its terms
+type NodeSelector struct {
+	NodeSelectorTerms []NodeSelectorTerm `yaml:"nodeSelectorTerms"`
+}
+
+// PreferredSchedulingTerm adds its weight to the score of all nodes that match its preference
+type PreferredSchedulingTerm struct {
+	Weight     int32            `yaml:"weight"`
+	Preference NodeSelectorTerm `yaml:"preference"`
+}
+
+// NodeSelectorTerm is matched by a node if the node matches all of its expressions
+type NodeSelectorTerm struct {
+	MatchExpressions []NodeSelectorRequirement `yaml:"matchExpressions"`
+}
+
+// NodeSelectorRequirement compares the label of a node with the given values
+type NodeSelectorRequirement struct {
+	Key      string   `yaml:"key"`
+	Operator string   `yaml:"operator"`
+	Values   []string `yaml:"values,omitempty"`
+}
+
+// validateScheduling checks the tolerations and the node affinity of the task. Whether the task is allowed to use
+// the values is decided by the job-executor-service when the job is created
+func (t *Task) validateScheduling() error {
+	for _, toleration := range t.Tolerations {
+		if err := toleration.validate(); err != nil {
+			return err
+		}
+	}
+
+	if t.Affinity == nil || t.Affinity.NodeAffinity == nil {
+		return nil
+	}
+
+	nodeAffinity := t.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if len(terms) == 0 {
+			return fmt.Errorf("required node affinity requires at least one nodeSelectorTerm")
+		}
+
+		for _, term := range terms {
+			if err := term.validate(); err != nil {
+				return fmt.Errorf("invalid required node affinity: %w", err)
+			}
+		}
+	}
+
+	for _, term := range nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		if term.Weight < 1 || term.Weight > 100 {
+			return fmt.Errorf("weight %d of preferred node affinity must be between 1 and 100", term.Weight)
+		}
+
+		if err := term.Preference.validate(); err != nil {
+			return fmt.Errorf("invalid preferred node affinity: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the operator and the effect of the toleration
+func (t Toleration) validate() error {
+	switch t.Operator {
+	case "", "Equal":
+		if t.Key == "" {
+			return fmt.Errorf("toleration with operator Equal requires a key")
+		}
+	case "Exists":
+		if t.Value != "" {
+			return fmt.Errorf("operator Exists of toleration %s must not have a value", t.Key)
+		}
+	default:
+		return fmt.Errorf("unknown operator %s of toleration %s, must be Equal or Exists", t.Operator, t.Key)
+	}
+
+	switch t.Effect {
+	case "", "NoSchedule", "PreferNoSchedule":
+		if t.TolerationSeconds != nil {
+			return fmt.Errorf("tolerationSeconds of toleration %s can only be used with effect NoExecute", t.Key)
+		}
+	case "NoExecute":
+	default:
+		return fmt.Errorf(
+			"unknown effect %s of toleration %s, must be NoSchedule, PreferNoSchedule or NoExecute", t.Effect, t.Key,
+		)
+	}
+
+	return nil
+}
+
+// validate checks that the term has expressions and that the values of each expression fit its operator
+func (t NodeSelectorTerm) validate() error {
+	if len(t.MatchExpressions) == 0 {
+		return fmt.Errorf("nodeSelectorTerm requires at least one matchExpression")
+	}
+
+	for _, expression := range t.MatchExpressions {
+		if expression.Key == "" {
+			return fmt.Errorf("matchExpressions require a key")
+		}
+
+		switch expression.Operator {
+		case "In", "NotIn":
+			if len(expression.Values) == 0 {
+				return fmt.Errorf("operator %s of key %s requires values", expression.Operator, expression.Key)
+			}
+		case "Exists", "DoesNotExist":
+			if len(expression.Values) != 0 {
+				return fmt.Errorf("operator %s of key %s must not have values", expression.Operator, expression.Key)
+			}
+		case "Gt", "Lt":
+			if len(expression.Values) != 1 {
+				return fmt.Errorf("operator %s of key %s requires exactly one value", expression.Operator, expression.Key)
+			}
+		default:
+			return fmt.Errorf(
+				"unknown operator %s of key %s, must be In, NotIn, Exists, DoesNotExist, Gt or Lt",
+				expression.Operator, expression.Key,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduling(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Performance tests"
+        image: "locustio/locust"
+        nodeSelector:
+          node-pool: "performance"
+        tolerations:
+          - key: "dedicated"
+            operator: "Equal"
+            value: "performance"
+            effect: "NoSchedule"
+        affinity:
+          nodeAffinity:
+            requiredDuringSchedulingIgnoredDuringExecution:
+              nodeSelectorTerms:
+                - matchExpressions:
+                    - key: "node.kubernetes.io/lifecycle"
+                      operator: "NotIn"
+                      values: ["spot"]
+            preferredDuringSchedulingIgnoredDuringExecution:
+              - weight: 10
+                preference:
+                  matchExpressions:
+                    - key: "disktype"
+                      operator: "Exists"
+        priorityClassName: "high-priority"
+        runtimeClassName: "gvisor"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, task := config.Actions[0].FindTaskByName("Performance tests")
+	require.True(t, found)
+
+	assert.Equal(t, map[string]string{"node-pool": "performance"}, task.NodeSelector)
+	assert.Equal(t, []Toleration{
+		{Key: "dedicated", Operator: "Equal", Value: "performance", Effect: "NoSchedule"},
+	}, task.Tolerations)
+	assert.Equal(t, &Affinity{
+		NodeAffinity: &NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &NodeSelector{
+				NodeSelectorTerms: []NodeSelectorTerm{
+					{
+						MatchExpressions: []NodeSelectorRequirement{
+							{Key: "node.kubernetes.io/lifecycle", Operator: "NotIn", Values: []string{"spot"}},
+						},
+					},
+				},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []PreferredSchedulingTerm{
+				{
+					Weight: 10,
+					Preference: NodeSelectorTerm{
+						MatchExpressions: []NodeSelectorRequirement{{Key: "disktype", Operator: "Exists"}},
+					},
+				},
+			},
+		},
+	}, task.Affinity)
+	assert.Equal(t, "high-priority", task.PriorityClassName)
+	assert.Equal(t, "gvisor", task.RuntimeClassName)
+}
+
+func TestInvalidScheduling(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskYaml      string
+		expectedError string
+	}{
+		{
+			name: "unknown toleration operator",
+			taskYaml: `
+        tolerations:
+          - key: "dedicated"
+            operator: "In"`,
+			expectedError: "unknown operator In of toleration dedicated, must be Equal or Exists",
+		},
+		{
+			name: "toleration with Equal and without key",
+			taskYaml: `
+        tolerations:
+          - value: "performance"`,
+			expectedError: "toleration with operator Equal requires a key",
+		},
+		{
+			name: "toleration with Exists and value",
+			taskYaml: `
+        tolerations:
+          - key: "dedicated"
+            operator: "Exists"
+            value: "performance"`,
+			expectedError: "operator Exists of toleration dedicated must not have a value",
+		},
+		{
+			name: "unknown toleration effect",
+			taskYaml: `
+        tolerations:
+          - key: "dedicated"
+            operator: "Exists"
+            effect: "NoRun"`,
+			expectedError: "unknown effect NoRun of toleration dedicated, must be NoSchedule, PreferNoSchedule or NoExecute",
+		},
+		{
+			name: "tolerationSeconds without NoExecute",
+			taskYaml: `
+        tolerations:
+          - key: "dedicated"
+            operator: "Exists"
+            effect: "NoSchedule"
+            tolerationSeconds: 60`,
+			expectedError: "tolerationSeconds of toleration dedicated can only be used with effect NoExecute",
+		},
+		{
+			name: "required node affinity without terms",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            requiredDuringSchedulingIgnoredDuringExecution:
+              nodeSelectorTerms: []`,
+			expectedError: "required node affinity requires at least one nodeSelectorTerm",
+		},
+		{
+			name: "node selector term without expressions",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            requiredDuringSchedulingIgnoredDuringExecution:
+              nodeSelectorTerms:
+                - matchExpressions: []`,
+			expectedError: "invalid required node affinity: nodeSelectorTerm requires at least one matchExpression",
+		},
+		{
+			name: "operator In without values",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            requiredDuringSchedulingIgnoredDuringExecution:
+              nodeSelectorTerms:
+                - matchExpressions:
+                    - key: "node-pool"
+                      operator: "In"`,
+			expectedError: "invalid required node affinity: operator In of key node-pool requires values",
+		},
+		{
+			name: "operator Exists with values",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            preferredDuringSchedulingIgnoredDuringExecution:
+              - weight: 10
+                preference:
+                  matchExpressions:
+                    - key: "disktype"
+                      operator: "Exists"
+                      values: ["ssd"]`,
+			expectedError: "invalid preferred node affinity: operator Exists of key disktype must not have values",
+		},
+		{
+			name: "operator Gt with two values",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            preferredDuringSchedulingIgnoredDuringExecution:
+              - weight: 10
+                preference:
+                  matchExpressions:
+                    - key: "cpus"
+                      operator: "Gt"
+                      values: ["4", "8"]`,
+			expectedError: "invalid preferred node affinity: operator Gt of key cpus requires exactly one value",
+		},
+		{
+			name: "unknown operator",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            preferredDuringSchedulingIgnoredDuringExecution:
+              - weight: 10
+                preference:
+                  matchExpressions:
+                    - key: "disktype"
+                      operator: "Equal"`,
+			expectedError: "invalid preferred node affinity: unknown operator Equal of key disktype, must be In, NotIn, Exists, DoesNotExist, Gt or Lt",
+		},
+		{
+			name: "invalid weight",
+			taskYaml: `
+        affinity:
+          nodeAffinity:
+            preferredDuringSchedulingIgnoredDuringExecution:
+              - weight: 0
+                preference:
+                  matchExpressions:
+                    - key: "disktype"
+                      operator: "Exists"`,
+			expectedError: "weight 0 of preferred node affinity must be between 1 and 100",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Performance tests"
+        image: "locustio/locust"` + test.taskYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Performance tests in action Run tests: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	taskTemplatesKey = "taskTemplates"
+	extendsKey       = "extends"
+)
+
+// taskListKeys contains the keys of an action that contain a list of tasks
+var taskListKeys = []string{"tasks", "onFailure", "finally"}
+
+// resolveTaskTemplates merges the task templates into all tasks that extend them. The templates are resolved on the
+// YAML level, such that only the fields that are actually set on a task override the fields of the template.
+func resolveTaskTemplates(yamlContent []byte) ([]byte, error) {
+	document := yaml.MapSlice{}
+	if err := yaml.Unmarshal(yamlContent, &document); err != nil {
+		return nil, err
+	}
+
+	templatesValue, ok := mapSliceValue(document, taskTemplatesKey)
+	if !ok {
+		// Without templates there is nothing to resolve, tasks with an extends field are rejected by the validation
+		return yamlContent, nil
+	}
+
+	templates, ok := templatesValue.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map of task templates", taskTemplatesKey)
+	}
+
+	for _, template := range templates {
+		templateFields, ok := template.Value.(yaml.MapSlice)
+		if !ok {
+			return nil, fmt.Errorf("task template %v must be a map", template.Key)
+		}
+
+		if _, ok := mapSliceValue(templateFields, extendsKey); ok {
+			return nil, fmt.Errorf("task template %v can't extend another template", template.Key)
+		}
+	}
+
+	actions, _ := mapSliceValue(document, "actions")
+	actionList, _ := actions.([]interface{})
+	for _, action := range actionList {
+		actionFields, ok := action.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+
+		for _, taskListKey := range taskListKeys {
+			tasks, _ := mapSliceValue(actionFields, taskListKey)
+			taskList, _ := tasks.([]interface{})
+			for taskIndex, task := range taskList {
+				taskFields, ok := task.(yaml.MapSlice)
+				if !ok {
+					continue
+				}
+
+				resolvedTask, err := extendTask(taskFields, templates)
+				if err != nil {
+					return nil, err
+				}
+
+				taskList[taskIndex] = resolvedTask
+			}
+		}
+	}
+
+	return yaml.Marshal(document)
+}
+
+// extendTask merges the task over the template it extends, tasks without an extends field are returned as they are
+func extendTask(task yaml.MapSlice, templates yaml.MapSlice) (yaml.MapSlice, error) {
+	extends, ok := mapSliceValue(task, extendsKey)
+	if !ok {
+		return task, nil
+	}
+
+	template, ok := mapSliceValue(templates, extends)
+	if !ok {
+		name, _ := mapSliceValue(task, "name")
+		return nil, fmt.Errorf("task %v extends unknown template %v", name, extends)
+	}
+
+	return mergeMapSlices(template.(yaml.MapSlice), task), nil
+}
+
+// mergeMapSlices deep-merges the override into the base map. Nested maps are merged, lists of named items (e.g. env)
+// are merged by the name of the items and all other values of the override replace the values of the base.
+func mergeMapSlices(base yaml.MapSlice, override yaml.MapSlice) yaml.MapSlice {
+	merged := make(yaml.MapSlice, 0, len(base)+len(override))
+	merged = append(merged, base...)
+
+	for _, item := range override {
+		index := mapSliceIndex(merged, item.Key)
+		if index < 0 {
+			merged = append(merged, item)
+			continue
+		}
+
+		merged[index] = yaml.MapItem{Key: item.Key, Value: mergeValues(merged[index].Value, item.Value)}
+	}
+
+	return merged
+}
+
+// mergeValues merges the override value into the base value
+func mergeValues(base interface{}, override interface{}) interface{} {
+	switch overrideValue := override.(type) {
+	case yaml.MapSlice:
+		if baseValue, ok := base.(yaml.MapSlice); ok {
+			return mergeMapSlices(baseValue, overrideValue)
+		}
+	case []interface{}:
+		if baseValue, ok := base.([]interface{}); ok && isNamedList(baseValue) && isNamedList(overrideValue) {
+			return mergeNamedLists(baseValue, overrideValue)
+		}
+	}
+
+	return override
+}
+
+// mergeNamedLists merges two lists of named items, items of the override replace items of the base with the same
+// name and all other items are appended
+func mergeNamedLists(base []interface{}, override []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(base)+len(override))
+	merged = append(merged, base...)
+
+	for _, item := range override {
+		name, _ := mapSliceValue(item.(yaml.MapSlice), "name")
+
+		replaced := false
+		for index, baseItem := range merged {
+			if baseName, _ := mapSliceValue(baseItem.(yaml.MapSlice), "name"); baseName == name {
+				merged[index] = item
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			merged = append(merged, item)
+		}
+	}
+
+	return merged
+}
+
+// isNamedList returns true if all items of the list are maps with a name
+func isNamedList(list []interface{}) bool {
+	for _, item := range list {
+		fields, ok := item.(yaml.MapSlice)
+		if !ok {
+			return false
+		}
+
+		if _, ok := mapSliceValue(fields, "name"); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mapSliceValue returns the value of the given key in the map
+func mapSliceValue(mapSlice yaml.MapSlice, key interface{}) (interface{}, bool) {
+	index := mapSliceIndex(mapSlice, key)
+	if index < 0 {
+		return nil, false
+	}
+
+	return mapSlice[index].Value, true
+}
+
+// mapSliceIndex returns the index of the given key in the map or -1 if the map doesn't contain the key
+func mapSliceIndex(mapSlice yaml.MapSlice, key interface{}) int {
+	for index, item := range mapSlice {
+		if item.Key == key {
+			return index
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTemplates(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+taskTemplates:
+  locust:
+    image: "locustio/locust"
+    cmd: ["locust"]
+    resources:
+      limits:
+        cpu: "1"
+        memory: "512Mi"
+      requests:
+        cpu: "50m"
+        memory: "128Mi"
+    securityContext:
+      runAsNonRoot: true
+      runAsUser: 1000
+    env:
+      - name: HOST
+        value: "$.data.deployment.deploymentURIsPublic[0]"
+        valueFrom: event
+      - name: USERS
+        value: "10"
+        valueFrom: string
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Run smoke tests"
+        extends: locust
+        args: ["-f", "/keptn/smoke.py"]
+      - name: "Run load tests"
+        extends: locust
+        image: "locustio/locust:2.8.6"
+        resources:
+          limits:
+            memory: "2Gi"
+        securityContext:
+          runAsUser: 2000
+        env:
+          - name: USERS
+            value: "100"
+            valueFrom: string
+          - name: SPAWN_RATE
+            value: "10"
+            valueFrom: string
+      - name: "Print files"
+        image: "alpine"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Run locust")
+	require.True(t, found)
+
+	found, smokeTests := action.FindTaskByName("Run smoke tests")
+	require.True(t, found)
+	assert.Equal(t, "locust", smokeTests.Extends)
+	assert.Equal(t, "locustio/locust", smokeTests.Image)
+	assert.Equal(t, []string{"locust"}, smokeTests.Cmd)
+	assert.Equal(t, []string{"-f", "/keptn/smoke.py"}, smokeTests.Args)
+	assert.Equal(t, "512Mi", smokeTests.Resources.Limits.Memory)
+	assert.Len(t, smokeTests.Env, 2)
+
+	found, loadTests := action.FindTaskByName("Run load tests")
+	require.True(t, found)
+	assert.Equal(t, "locustio/locust:2.8.6", loadTests.Image)
+	assert.Equal(t, []string{"locust"}, loadTests.Cmd)
+	assert.Equal(t, ResourceList{CPU: "1", Memory: "2Gi"}, loadTests.Resources.Limits)
+	assert.Equal(t, ResourceList{CPU: "50m", Memory: "128Mi"}, loadTests.Resources.Requests)
+	require.NotNil(t, loadTests.SecurityContext.RunAsNonRoot)
+	assert.True(t, *loadTests.SecurityContext.RunAsNonRoot)
+	require.NotNil(t, loadTests.SecurityContext.RunAsUser)
+	assert.Equal(t, int64(2000), *loadTests.SecurityContext.RunAsUser)
+	assert.Equal(t, []Env{
+		{Name: "HOST", Value: "$.data.deployment.deploymentURIsPublic[0]", ValueFrom: "event"},
+		{Name: "USERS", Value: "100", ValueFrom: "string"},
+		{Name: "SPAWN_RATE", Value: "10", ValueFrom: "string"},
+	}, loadTests.Env)
+
+	found, printFiles := action.FindTaskByName("Print files")
+	require.True(t, found)
+	assert.Equal(t, Task{Name: "Print files", Image: "alpine"}, *printFiles)
+}
+
+func TestTaskTemplatesInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		configYaml    string
+		expectedError string
+	}{
+		{
+			name: "unknown template",
+			configYaml: `
+apiVersion: v2
+taskTemplates:
+  alpine:
+    image: "alpine"
+actions:
+  - name: "Print files"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Print files"
+        extends: busybox
+`,
+			expectedError: "task Print files extends unknown template busybox",
+		},
+		{
+			name: "no templates",
+			configYaml: `
+apiVersion: v2
+actions:
+  - name: "Print files"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Print files"
+        extends: alpine
+`,
+			expectedError: "task Print files in action Print files extends unknown template alpine",
+		},
+		{
+			name: "template extends template",
+			configYaml: `
+apiVersion: v2
+taskTemplates:
+  alpine:
+    image: "alpine"
+  ls:
+    extends: alpine
+    cmd: ["ls"]
+actions:
+  - name: "Print files"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Print files"
+        extends: ls
+`,
+			expectedError: "task template ls can't extend another template",
+		},
+		{
+			name: "invalid template field",
+			configYaml: `
+apiVersion: v2
+taskTemplates:
+  alpine:
+    image: "alpine"
+    imageTag: "latest"
+actions:
+  - name: "Print files"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Print files"
+        extends: alpine
+`,
+			expectedError: "field imageTag not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config, err := NewConfig([]byte(test.configYaml))
+			assert.ErrorContains(t, err, test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
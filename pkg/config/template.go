@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// templateFunctions is the restricted set of functions that can be used in templated task fields
+var templateFunctions = template.FuncMap{
+	"default": templateDefault,
+	"quote":   templateQuote,
+	"lower":   templateLower,
+	"upper":   templateUpper,
+}
+
+// templateDefault returns the given default value if the value is missing or empty, e.g.
+// {{ index .data "tag" | default "latest" }}
+func templateDefault(defaultValue interface{}, value interface{}) interface{} {
+	if value == nil || value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// templateQuote returns the value as double-quoted string
+func templateQuote(value interface{}) string {
+	return strconv.Quote(templateString(value))
+}
+
+// templateLower returns the value as lower case string
+func templateLower(value interface{}) string {
+	return strings.ToLower(templateString(value))
+}
+
+// templateUpper returns the value as upper case string
+func templateUpper(value interface{}) string {
+	return strings.ToUpper(templateString(value))
+}
+
+// templateString converts the value of an event property to a string, nil values, e.g. of missing properties read
+// with index, are converted to an empty string
+func templateString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Render returns a copy of the task in which the image, cmd, args, working directory and annotations are rendered as
+// Go templates against the given event, e.g. {{ .data.configurationChange.values.image }}. Only tasks with templated
+// set are rendered, all other tasks are returned unchanged
+func (t Task) Render(eventData map[string]interface{}) (Task, error) {
+	if !t.Templated {
+		return t, nil
+	}
+
+	var err error
+
+	rendered := t
+	if rendered.Image, err = renderTemplate("image", t.Image, eventData); err != nil {
+		return Task{}, err
+	}
+
+	if rendered.WorkingDir, err = renderTemplate("workingDir", t.WorkingDir, eventData); err != nil {
+		return Task{}, err
+	}
+
+	if rendered.Cmd, err = renderTemplates("cmd", t.Cmd, eventData); err != nil {
+		return Task{}, err
+	}
+
+	if rendered.Args, err = renderTemplates("args", t.Args, eventData); err != nil {
+		return Task{}, err
+	}
+
+	if t.Annotations != nil {
+		rendered.Annotations = make(map[string]string, len(t.Annotations))
+		for key, value := range t.Annotations {
+			if rendered.Annotations[key], err = renderTemplate("annotation "+key, value, eventData); err != nil {
+				return Task{}, err
+			}
+		}
+	}
+
+	return rendered, nil
+}
+
+// validateTemplates checks that all templated fields of the task can be parsed, if the task is templated
+func (t Task) validateTemplates() error {
+	if !t.Templated {
+		return nil
+	}
+
+	fields := map[string]string{
+		"image":      t.Image,
+		"workingDir": t.WorkingDir,
+	}
+
+	for index, cmd := range t.Cmd {
+		fields[fmt.Sprintf("cmd[%d]", index)] = cmd
+	}
+
+	for index, arg := range t.Args {
+		fields[fmt.Sprintf("args[%d]", index)] = arg
+	}
+
+	for key, value := range t.Annotations {
+		fields["annotation "+key] = value
+	}
+
+	for field, value := range fields {
+		if _, err := parseTemplate(field, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderTemplates renders every element of the given list as template
+func renderTemplates(field string, values []string, eventData map[string]interface{}) ([]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	rendered := make([]string, len(values))
+	for index, value := range values {
+		var err error
+		rendered[index], err = renderTemplate(fmt.Sprintf("%s[%d]", field, index), value, eventData)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rendered, nil
+}
+
+// renderTemplate renders the given value as template against the event, properties that don't exist in the event are
+// reported as error
+func renderTemplate(field string, value string, eventData map[string]interface{}) (string, error) {
+	// Values without any template actions don't have to be parsed at all
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+
+	tmpl, err := parseTemplate(field, value)
+	if err != nil {
+		return "", err
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, eventData); err != nil {
+		return "", fmt.Errorf("unable to render %s: %w", field, err)
+	}
+
+	return rendered.String(), nil
+}
+
+// parseTemplate parses the value as template with the restricted set of template functions
+func parseTemplate(field string, value string) (*template.Template, error) {
+	tmpl, err := template.New(field).Funcs(templateFunctions).Option("missingkey=error").Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template in %s: %w", field, err)
+	}
+
+	return tmpl, nil
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRender(t *testing.T) {
+	eventData := map[string]interface{}{
+		"type": "sh.keptn.event.deployment.triggered",
+		"data": map[string]interface{}{
+			"project": "sockshop",
+			"stage":   "Production",
+			"configurationChange": map[string]interface{}{
+				"values": map[string]interface{}{
+					"image": "docker.io/keptnexamples/carts:0.13.1",
+				},
+			},
+		},
+	}
+
+	task := Task{
+		Name:       "Deploy",
+		Templated:  true,
+		Image:      "{{ .data.configurationChange.values.image }}",
+		Cmd:        []string{"helm", "{{ index .data \"action\" | default \"upgrade\" }}"},
+		Args:       []string{"--namespace", "{{ .data.project }}-{{ .data.stage | lower }}", "--set", "image={{ .data.configurationChange.values.image | quote }}"},
+		WorkingDir: "/keptn/{{ .data.project | upper }}",
+		Annotations: map[string]string{
+			"keptn.sh/type":   "{{ .type }}",
+			"keptn.sh/static": "static",
+		},
+	}
+
+	rendered, err := task.Render(eventData)
+	require.NoError(t, err)
+
+	assert.Equal(t, "docker.io/keptnexamples/carts:0.13.1", rendered.Image)
+	assert.Equal(t, []string{"helm", "upgrade"}, rendered.Cmd)
+	assert.Equal(t, []string{"--namespace", "sockshop-production", "--set", "image=\"docker.io/keptnexamples/carts:0.13.1\""}, rendered.Args)
+	assert.Equal(t, "/keptn/SOCKSHOP", rendered.WorkingDir)
+	assert.Equal(t, map[string]string{
+		"keptn.sh/type":   "sh.keptn.event.deployment.triggered",
+		"keptn.sh/static": "static",
+	}, rendered.Annotations)
+
+	// The original task must not be modified by rendering
+	assert.Equal(t, "{{ .data.configurationChange.values.image }}", task.Image)
+	assert.Equal(t, "{{ .type }}", task.Annotations["keptn.sh/type"])
+}
+
+func TestTaskRenderMissingValues(t *testing.T) {
+	task := Task{
+		Name:      "Missing values",
+		Templated: true,
+		Image:     "alpine",
+		Args:      []string{"image={{ .data.configurationChange.values.imgae }}"},
+	}
+
+	_, err := task.Render(map[string]interface{}{"data": map[string]interface{}{
+		"configurationChange": map[string]interface{}{
+			"values": map[string]interface{}{"image": "docker.io/keptnexamples/carts:0.13.1"},
+		},
+	}})
+	assert.ErrorContains(t, err, "unable to render args[0]")
+}
+
+func TestTaskRenderOptionalValues(t *testing.T) {
+	task := Task{
+		Name:      "Optional values",
+		Templated: true,
+		Image:     "alpine:{{ index .data \"tag\" | default \"latest\" }}",
+		Args:      []string{"{{ index .data \"missing\" | upper }}"},
+	}
+
+	rendered, err := task.Render(map[string]interface{}{"data": map[string]interface{}{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "alpine:latest", rendered.Image)
+	assert.Equal(t, []string{""}, rendered.Args)
+}
+
+func TestTaskRenderNotTemplated(t *testing.T) {
+	task := Task{
+		Name:  "Literal braces",
+		Image: "bitnami/kubectl",
+		Args:  []string{"get", "pods", "-o", "go-template={{.metadata.name}}"},
+	}
+
+	rendered, err := task.Render(map[string]interface{}{"data": map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.Equal(t, task, rendered)
+}
+
+func TestTaskRenderRestrictedFunctions(t *testing.T) {
+	task := Task{
+		Name:      "Forbidden function",
+		Templated: true,
+		Image:     "{{ env \"HOME\" }}",
+	}
+
+	_, err := task.Render(map[string]interface{}{})
+	assert.Error(t, err)
+}
+
+func TestInvalidTaskTemplate(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Deploy image"
+        templated: true
+        image: "{{ .data.image "
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.ErrorContains(t, err, "invalid task Deploy image in action Deploy")
+	assert.Nil(t, config)
+}
+
+func TestLiteralBracesWithoutTemplated(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "List pods"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "kubectl"
+        image: "bitnami/kubectl"
+        args:
+          - "get"
+          - "pods"
+          - "-o"
+          - "go-template={{ .metadata.name "
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	rendered, err := config.Actions[0].Tasks[0].Render(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "go-template={{ .metadata.name ", rendered.Args[3])
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// APIVersionV2 is the apiVersion of the v2 job configuration schema
+	APIVersionV2 = "v2"
+	// APIVersionV3 is the apiVersion of the v3 job configuration schema
+	APIVersionV3 = "v3"
+)
+
+// configParsers contains a parser for each supported apiVersion, every parser normalizes the schema of its version
+// into the Config model
+var configParsers = map[string]func(yamlContent []byte) (*Config, error){
+	APIVersionV2: parseV2Config,
+	APIVersionV3: parseV3Config,
+}
+
+// SupportedAPIVersions returns a sorted list of all apiVersions that can be parsed by NewConfig
+func SupportedAPIVersions() []string {
+	versions := make([]string, 0, len(configParsers))
+	for version := range configParsers {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+
+	return versions
+}
+
+// parseV2Config parses a v2 configuration, the v2 schema is identical to the Config model
+func parseV2Config(yamlContent []byte) (*Config, error) {
+	config := Config{}
+	err := yaml.UnmarshalStrict(yamlContent, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// MarshalV2 serializes the configuration in the v2 schema. Configurations of all versions are normalized into the
+// Config model when they are parsed, which is identical to the v2 schema, so no information is lost
+func (c *Config) MarshalV2() ([]byte, error) {
+	v2 := *c
+	apiVersion := APIVersionV2
+	v2.APIVersion = &apiVersion
+
+	return yaml.Marshal(&v2)
+}
+
+// eventV3 is the v3 schema of an Event. In contrast to v2 the event names are matched exactly by default and all
+// filters of the event are expressed by a single condition
+type eventV3 struct {
+	Name      string     `yaml:"name"`
+	MatchMode string     `yaml:"matchMode,omitempty"`
+	Condition *Condition `yaml:"condition,omitempty"`
+}
+
+// parseV3Config parses a v3 configuration and converts it into the Config model. Only the events of the actions
+// differ from the v2 schema, all other fields are unmarshalled directly into the Config model, such that fields which
+// are added to the model are supported by both versions
+func parseV3Config(yamlContent []byte) (*Config, error) {
+	document := yaml.MapSlice{}
+	err := yaml.UnmarshalStrict(yamlContent, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []yaml.MapSlice
+	document, err = removeMapSliceKey(document, "actions", &actions)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	err = unmarshalStrictValue(document, config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.Actions = make([]Action, len(actions))
+	for actionIndex, action := range actions {
+		var eventsV3 []eventV3
+		action, err = removeMapSliceKey(action, "events", &eventsV3)
+		if err != nil {
+			return nil, err
+		}
+
+		err = unmarshalStrictValue(action, &config.Actions[actionIndex])
+		if err != nil {
+			return nil, err
+		}
+
+		events := make([]Event, len(eventsV3))
+		for eventIndex, event := range eventsV3 {
+			events[eventIndex] = Event{
+				Name:      event.Name,
+				MatchMode: event.MatchMode,
+			}
+
+			if event.MatchMode == "" {
+				events[eventIndex].MatchMode = EventMatchModeExact
+			}
+
+			if event.Condition != nil {
+				events[eventIndex].All = []Condition{*event.Condition}
+			}
+		}
+
+		config.Actions[actionIndex].Events = events
+	}
+
+	return config, nil
+}
+
+// removeMapSliceKey removes the key from the map and unmarshalls its value into out, the map is returned unchanged if
+// it doesn't contain the key
+func removeMapSliceKey(mapSlice yaml.MapSlice, key string, out interface{}) (yaml.MapSlice, error) {
+	index := mapSliceIndex(mapSlice, key)
+	if index < 0 {
+		return mapSlice, nil
+	}
+
+	if err := unmarshalStrictValue(mapSlice[index].Value, out); err != nil {
+		return nil, err
+	}
+
+	return append(mapSlice[:index:index], mapSlice[index+1:]...), nil
+}
+
+// unmarshalStrictValue unmarshalls a value of a yaml.MapSlice into out, unknown fields are reported as an error
+func unmarshalStrictValue(value interface{}, out interface{}) error {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return yaml.UnmarshalStrict(content, out)
+}
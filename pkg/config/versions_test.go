@@ -0,0 +1,93 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+// v3ConfigWithAllFields sets every field of the Config and the Action model in the v3 schema
+const v3ConfigWithAllFields = `
+apiVersion: v3
+inherit: true
+include:
+  - "job/common/locust.yaml"
+taskTemplates:
+  locust:
+    image: "locustio/locust"
+actions:
+  - name: "Run locust"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    when:
+      stage:
+        - "dev"
+    tasks:
+      - name: "Run locust smoke tests"
+        image: "locustio/locust"
+    silent: true
+    parallel: true
+    parallelism: 2
+    failureStrategy: "waitForAll"
+    onFailure:
+      - name: "Notify"
+        image: "alpine"
+    finally:
+      - name: "Cleanup"
+        image: "alpine"
+    workspace:
+      size: "1Gi"
+`
+
+func TestParseV3ConfigKeepsAllFields(t *testing.T) {
+	document := yaml.MapSlice{}
+	require.NoError(t, yaml.Unmarshal([]byte(v3ConfigWithAllFields), &document))
+	actionValue, _ := mapSliceValue(document, "actions")
+	action := actionValue.([]interface{})[0].(yaml.MapSlice)
+
+	// The test configuration has to be extended if a field is added to the model
+	for _, test := range []struct {
+		fields   yaml.MapSlice
+		itemType reflect.Type
+	}{
+		{fields: document, itemType: reflect.TypeOf(Config{})},
+		{fields: action, itemType: reflect.TypeOf(Action{})},
+	} {
+		for _, yamlKey := range yamlKeysOf(test.itemType) {
+			_, ok := mapSliceValue(test.fields, yamlKey)
+			assert.True(t, ok, "field %s of %s is missing in the test configuration", yamlKey, test.itemType.Name())
+		}
+	}
+
+	config, err := parseV3Config([]byte(v3ConfigWithAllFields))
+	require.NoError(t, err)
+	require.Len(t, config.Actions, 1)
+
+	for _, item := range []interface{}{*config, config.Actions[0]} {
+		value := reflect.ValueOf(item)
+		for fieldIndex := 0; fieldIndex < value.NumField(); fieldIndex++ {
+			field := value.Type().Field(fieldIndex)
+			if field.IsExported() {
+				assert.False(t, value.Field(fieldIndex).IsZero(), "field %s of %s was not parsed from v3", field.Name,
+					value.Type().Name())
+			}
+		}
+	}
+}
+
+// yamlKeysOf returns the yaml keys of all fields of the struct type
+func yamlKeysOf(structType reflect.Type) []string {
+	var keys []string
+	for fieldIndex := 0; fieldIndex < structType.NumField(); fieldIndex++ {
+		tag := structType.Field(fieldIndex).Tag.Get("yaml")
+		if key := strings.Split(tag, ",")[0]; key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// VolumeMediumMemory is the medium of a job volume that is backed by memory (tmpfs) instead of the disk of the node
+const VolumeMediumMemory = "Memory"
+
+// JobVolume overrides the size, mount path and medium of the volume the files of a task are mounted into. Empty
+// values are replaced by the defaults of the job-executor-service
+type JobVolume struct {
+	Size      string `yaml:"size,omitempty"`
+	MountPath string `yaml:"mountPath,omitempty"`
+	Medium    string `yaml:"medium,omitempty"`
+}
+
+// validateVolume checks the mount path and the medium of the job volume of the task. The size is parsed when the job
+// is created, since it has to be compared against the maximum volume size of the job-executor-service
+func (t *Task) validateVolume() error {
+	if t.Volume == nil {
+		return nil
+	}
+
+	if t.Volume.MountPath != "" {
+		if !path.IsAbs(t.Volume.MountPath) {
+			return fmt.Errorf("mountPath %s of volume must be an absolute path", t.Volume.MountPath)
+		}
+
+		if path.Clean(t.Volume.MountPath) == "/" {
+			return fmt.Errorf("mountPath of volume must not be /")
+		}
+	}
+
+	if t.Volume.Medium != "" && t.Volume.Medium != VolumeMediumMemory {
+		return fmt.Errorf("unknown medium %s of volume, must be %s or empty", t.Volume.Medium, VolumeMediumMemory)
+	}
+
+	return nil
+}
+
+// Volume mounts the keys of an existing ConfigMap or Secret as files into the job container. Volumes are always
+// mounted read-only
+type Volume struct {
+	MountPath string        `yaml:"mountPath"`
+	ConfigMap *VolumeSource `yaml:"configMap,omitempty"`
+	Secret    *VolumeSource `yaml:"secret,omitempty"`
+}
+
+// VolumeSource references the ConfigMap or Secret of a volume. If no items are given, all keys are mounted as files
+// named after the key
+type VolumeSource struct {
+	Name        string       `yaml:"name"`
+	Items       []VolumeItem `yaml:"items,omitempty"`
+	DefaultMode *int32       `yaml:"defaultMode,omitempty"`
+}
+
+// VolumeItem mounts a single key of a ConfigMap or Secret at the given path relative to the mount path of the volume,
+// the path defaults to the key
+type VolumeItem struct {
+	Key  string `yaml:"key"`
+	Path string `yaml:"path,omitempty"`
+	Mode *int32 `yaml:"mode,omitempty"`
+}
+
+// maxVolumeFileMode is the maximum file mode of files in a volume
+const maxVolumeFileMode = 0777
+
+// GetPath returns the path of the file the key is mounted at
+func (i VolumeItem) GetPath() string {
+	if i.Path == "" {
+		return i.Key
+	}
+
+	return i.Path
+}
+
+// validateVolumes checks that each volume of the task references either a ConfigMap or a Secret and that the volumes
+// are mounted at different absolute paths
+func (t *Task) validateVolumes() error {
+	mountPaths := make(map[string]bool, len(t.Volumes))
+	if t.Volume != nil && t.Volume.MountPath != "" {
+		mountPaths[path.Clean(t.Volume.MountPath)] = true
+	}
+
+	for _, volume := range t.Volumes {
+		if !path.IsAbs(volume.MountPath) {
+			return fmt.Errorf("mountPath %s of volume must be an absolute path", volume.MountPath)
+		}
+
+		if path.Clean(volume.MountPath) == "/" {
+			return fmt.Errorf("mountPath of volume must not be /")
+		}
+
+		if mountPaths[path.Clean(volume.MountPath)] {
+			return fmt.Errorf("mountPath %s is used by more than one volume", volume.MountPath)
+		}
+		mountPaths[path.Clean(volume.MountPath)] = true
+
+		source, kind := volume.ConfigMap, "configMap"
+		if volume.Secret != nil {
+			if volume.ConfigMap != nil {
+				return fmt.Errorf("volume at %s can't use both configMap and secret", volume.MountPath)
+			}
+			source, kind = volume.Secret, "secret"
+		}
+
+		if source == nil {
+			return fmt.Errorf("volume at %s requires either configMap or secret", volume.MountPath)
+		}
+
+		if err := source.validate(); err != nil {
+			return fmt.Errorf("invalid %s of volume at %s: %w", kind, volume.MountPath, err)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the name, the items and the modes of the volume source
+func (s *VolumeSource) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if err := validateFileMode(s.DefaultMode); err != nil {
+		return fmt.Errorf("invalid defaultMode: %w", err)
+	}
+
+	paths := make(map[string]bool, len(s.Items))
+	for _, item := range s.Items {
+		if item.Key == "" {
+			return fmt.Errorf("items require a key")
+		}
+
+		itemPath := path.Clean(item.GetPath())
+		if path.IsAbs(itemPath) || itemPath == "." || itemPath == ".." || strings.HasPrefix(itemPath, "../") {
+			return fmt.Errorf("path %s of key %s must be a relative path within the volume", item.GetPath(), item.Key)
+		}
+
+		if paths[itemPath] {
+			return fmt.Errorf("path %s is used by more than one key", item.GetPath())
+		}
+		paths[itemPath] = true
+
+		if err := validateFileMode(item.Mode); err != nil {
+			return fmt.Errorf("invalid mode of key %s: %w", item.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// validateFileMode checks that the file mode is within 0 and 0777, if it is set
+func validateFileMode(mode *int32) error {
+	if mode != nil && (*mode < 0 || *mode > maxVolumeFileMode) {
+		return fmt.Errorf("mode %o must be between 0 and %o", *mode, maxVolumeFileMode)
+	}
+
+	return nil
+}
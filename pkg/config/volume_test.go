@@ -0,0 +1,279 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobVolume(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Generate report"
+        image: "alpine"
+        volume:
+          size: "1Gi"
+          mountPath: "/data"
+          medium: "Memory"
+      - name: "Upload report"
+        image: "alpine"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, task := config.Actions[0].FindTaskByName("Generate report")
+	require.True(t, found)
+	assert.Equal(t, &JobVolume{Size: "1Gi", MountPath: "/data", Medium: VolumeMediumMemory}, task.Volume)
+
+	found, task = config.Actions[0].FindTaskByName("Upload report")
+	require.True(t, found)
+	assert.Nil(t, task.Volume)
+}
+
+func TestInvalidJobVolume(t *testing.T) {
+	tests := []struct {
+		name          string
+		volumeYaml    string
+		expectedError string
+	}{
+		{
+			name: "relative mount path",
+			volumeYaml: `
+          mountPath: "data"`,
+			expectedError: "mountPath data of volume must be an absolute path",
+		},
+		{
+			name: "root mount path",
+			volumeYaml: `
+          mountPath: "/"`,
+			expectedError: "mountPath of volume must not be /",
+		},
+		{
+			name: "unknown medium",
+			volumeYaml: `
+          medium: "HugePages"`,
+			expectedError: "unknown medium HugePages of volume, must be Memory or empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Generate report"
+        image: "alpine"
+        volume:` + test.volumeYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Generate report in action Run tests: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
+
+func TestVolumes(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Apply manifests"
+        image: "bitnami/kubectl"
+        volumes:
+          - mountPath: "/kube"
+            secret:
+              name: "kubeconfig"
+              defaultMode: 0400
+              items:
+                - key: "config"
+                - key: "ca.crt"
+                  path: "certs/ca.crt"
+                  mode: 0444
+          - mountPath: "/etc/tool"
+            configMap:
+              name: "tool-config"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, task := config.Actions[0].FindTaskByName("Apply manifests")
+	require.True(t, found)
+	require.Len(t, task.Volumes, 2)
+
+	defaultMode := int32(0400)
+	mode := int32(0444)
+	assert.Equal(t, Volume{
+		MountPath: "/kube",
+		Secret: &VolumeSource{
+			Name:        "kubeconfig",
+			DefaultMode: &defaultMode,
+			Items: []VolumeItem{
+				{Key: "config"},
+				{Key: "ca.crt", Path: "certs/ca.crt", Mode: &mode},
+			},
+		},
+	}, task.Volumes[0])
+	assert.Equal(t, Volume{MountPath: "/etc/tool", ConfigMap: &VolumeSource{Name: "tool-config"}}, task.Volumes[1])
+
+	assert.Equal(t, "config", task.Volumes[0].Secret.Items[0].GetPath())
+	assert.Equal(t, "certs/ca.crt", task.Volumes[0].Secret.Items[1].GetPath())
+}
+
+func TestInvalidVolumes(t *testing.T) {
+	tests := []struct {
+		name          string
+		taskYaml      string
+		expectedError string
+	}{
+		{
+			name: "relative mount path",
+			taskYaml: `
+        volumes:
+          - mountPath: "kube"
+            secret:
+              name: "kubeconfig"`,
+			expectedError: "mountPath kube of volume must be an absolute path",
+		},
+		{
+			name: "duplicate mount path",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"
+            secret:
+              name: "kubeconfig"
+          - mountPath: "/kube/"
+            configMap:
+              name: "tool-config"`,
+			expectedError: "mountPath /kube/ is used by more than one volume",
+		},
+		{
+			name: "mount path of the job volume",
+			taskYaml: `
+        volume:
+          mountPath: "/data"
+        volumes:
+          - mountPath: "/data"
+            configMap:
+              name: "tool-config"`,
+			expectedError: "mountPath /data is used by more than one volume",
+		},
+		{
+			name: "missing source",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"`,
+			expectedError: "volume at /kube requires either configMap or secret",
+		},
+		{
+			name: "configMap and secret",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"
+            configMap:
+              name: "tool-config"
+            secret:
+              name: "kubeconfig"`,
+			expectedError: "volume at /kube can't use both configMap and secret",
+		},
+		{
+			name: "missing name",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"
+            secret:
+              defaultMode: 0400`,
+			expectedError: "invalid secret of volume at /kube: name is required",
+		},
+		{
+			name: "missing key",
+			taskYaml: `
+        volumes:
+          - mountPath: "/etc/tool"
+            configMap:
+              name: "tool-config"
+              items:
+                - path: "tool.conf"`,
+			expectedError: "invalid configMap of volume at /etc/tool: items require a key",
+		},
+		{
+			name: "item path outside of the volume",
+			taskYaml: `
+        volumes:
+          - mountPath: "/etc/tool"
+            configMap:
+              name: "tool-config"
+              items:
+                - key: "tool.conf"
+                  path: "../tool.conf"`,
+			expectedError: "invalid configMap of volume at /etc/tool: path ../tool.conf of key tool.conf must be a relative path within the volume",
+		},
+		{
+			name: "duplicate item path",
+			taskYaml: `
+        volumes:
+          - mountPath: "/etc/tool"
+            configMap:
+              name: "tool-config"
+              items:
+                - key: "tool.conf"
+                - key: "tool-v2.conf"
+                  path: "tool.conf"`,
+			expectedError: "invalid configMap of volume at /etc/tool: path tool.conf is used by more than one key",
+		},
+		{
+			name: "invalid default mode",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"
+            secret:
+              name: "kubeconfig"
+              defaultMode: 01777`,
+			expectedError: "invalid secret of volume at /kube: invalid defaultMode: mode 1777 must be between 0 and 777",
+		},
+		{
+			name: "invalid item mode",
+			taskYaml: `
+        volumes:
+          - mountPath: "/kube"
+            secret:
+              name: "kubeconfig"
+              items:
+                - key: "config"
+                  mode: 01000`,
+			expectedError: "invalid secret of volume at /kube: invalid mode of key config: mode 1000 must be between 0 and 777",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Deploy"
+    events:
+      - name: "sh.keptn.event.deployment.triggered"
+    tasks:
+      - name: "Apply manifests"
+        image: "bitnami/kubectl"` + test.taskYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid task Apply manifests in action Deploy: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
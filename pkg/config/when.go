@@ -0,0 +1,91 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/gobwas/glob"
+)
+
+// When restricts an action to events of specific projects, stages, services and labels. Every field contains a list
+// of glob patterns, the event matches a field if its value matches one of the patterns. Empty fields don't restrict
+// the action
+type When struct {
+	Project []string            `yaml:"project,omitempty"`
+	Stage   []string            `yaml:"stage,omitempty"`
+	Service []string            `yaml:"service,omitempty"`
+	Labels  map[string][]string `yaml:"labels,omitempty"`
+}
+
+// IsMatch indicates whether the project, stage, service and labels of the given event match all selectors
+func (w *When) IsMatch(jsonEventData interface{}) bool {
+	selectors := map[string][]string{
+		"$.data.project": w.Project,
+		"$.data.stage":   w.Stage,
+		"$.data.service": w.Service,
+	}
+
+	for label, patterns := range w.Labels {
+		selectors[fmt.Sprintf("$.data.labels[%q]", label)] = patterns
+	}
+
+	for property, patterns := range selectors {
+		if len(patterns) == 0 {
+			continue
+		}
+
+		value, err := jsonpath.Get(property, jsonEventData)
+		if err != nil || value == nil {
+			return false
+		}
+
+		if !isAnyGlobMatch(patterns, fmt.Sprint(value)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validate checks that all patterns of the selectors are valid glob patterns
+func (w *When) validate() error {
+	selectors := map[string][]string{
+		"project": w.Project,
+		"stage":   w.Stage,
+		"service": w.Service,
+	}
+
+	for label, patterns := range w.Labels {
+		selectors["label "+label] = patterns
+	}
+
+	// The selectors are sorted to report errors deterministically
+	names := make([]string, 0, len(selectors))
+	for name := range selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, pattern := range selectors[name] {
+			if _, err := glob.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid glob pattern %s for %s: %w", pattern, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isAnyGlobMatch checks if the value matches one of the glob patterns, invalid patterns never match
+func isAnyGlobMatch(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		compiled, err := glob.Compile(pattern)
+		if err == nil && compiled.Match(value) {
+			return true
+		}
+	}
+
+	return false
+}
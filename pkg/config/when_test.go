@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScopedEvent(project string, stage string, service string, labels map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "sh.keptn.event.test.triggered",
+		"data": map[string]interface{}{
+			"project": project,
+			"stage":   stage,
+			"service": service,
+			"labels":  labels,
+		},
+	}
+}
+
+func TestActionWhen(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run load tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    when:
+      project: ["sockshop", "podtato-*"]
+      stage: ["staging", "perf-*"]
+      labels:
+        team: ["platform"]
+        load-test: ["enabled", "true"]
+    tasks:
+      - name: "Run locust"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	enabledLabels := map[string]interface{}{"team": "platform", "load-test": "enabled"}
+
+	tests := []struct {
+		name     string
+		event    map[string]interface{}
+		expected bool
+	}{
+		{
+			name:     "all selectors match",
+			event:    newScopedEvent("sockshop", "staging", "carts", enabledLabels),
+			expected: true,
+		},
+		{
+			name:     "glob patterns match",
+			event:    newScopedEvent("podtato-head", "perf-eu", "helloservice", enabledLabels),
+			expected: true,
+		},
+		{
+			name:     "stage doesn't match",
+			event:    newScopedEvent("sockshop", "production", "carts", enabledLabels),
+			expected: false,
+		},
+		{
+			name:     "project doesn't match",
+			event:    newScopedEvent("other", "staging", "carts", enabledLabels),
+			expected: false,
+		},
+		{
+			name:     "label doesn't match",
+			event:    newScopedEvent("sockshop", "staging", "carts", map[string]interface{}{"team": "platform", "load-test": "disabled"}),
+			expected: false,
+		},
+		{
+			name:     "label is missing",
+			event:    newScopedEvent("sockshop", "staging", "carts", map[string]interface{}{"load-test": "true"}),
+			expected: false,
+		},
+		{
+			name:     "no labels",
+			event:    newScopedEvent("sockshop", "staging", "carts", nil),
+			expected: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, config.IsEventMatch("sh.keptn.event.test.triggered", test.event))
+		})
+	}
+}
+
+func TestActionWhenServiceOnly(t *testing.T) {
+	when := When{Service: []string{"carts"}}
+
+	assert.True(t, when.IsMatch(newScopedEvent("sockshop", "production", "carts", nil)))
+	assert.False(t, when.IsMatch(newScopedEvent("sockshop", "production", "carts-db", nil)))
+	assert.False(t, when.IsMatch(map[string]interface{}{}))
+	assert.True(t, (&When{}).IsMatch(map[string]interface{}{}))
+}
+
+func TestInvalidActionWhen(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Run load tests"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    when:
+      stage: ["staging[", "production"]
+    tasks:
+      - name: "Run locust"
+        image: "locustio/locust"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	assert.ErrorContains(t, err, "invalid when in action Run load tests: invalid glob pattern staging[ for stage")
+	assert.Nil(t, config)
+}
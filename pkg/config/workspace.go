@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"path"
+)
+
+// DefaultWorkspaceMountPath is the path the workspace is mounted at if the workspace has no mount path
+const DefaultWorkspaceMountPath = "/workspace"
+
+// workspaceAccessModes contains the access modes of a PersistentVolumeClaim that can be used for a workspace
+var workspaceAccessModes = []string{"ReadWriteOnce", "ReadWriteOncePod", "ReadWriteMany"}
+
+// Workspace is a persistent volume that is shared by all tasks of an action. It's created before the first task is
+// started and deleted after the action has finished
+type Workspace struct {
+	Size             string `yaml:"size"`
+	MountPath        string `yaml:"mountPath,omitempty"`
+	StorageClassName string `yaml:"storageClassName,omitempty"`
+	AccessMode       string `yaml:"accessMode,omitempty"`
+}
+
+// GetMountPath returns the path the workspace is mounted at in the job containers
+func (w *Workspace) GetMountPath() string {
+	if w.MountPath == "" {
+		return DefaultWorkspaceMountPath
+	}
+
+	return w.MountPath
+}
+
+// GetAccessMode returns the access mode of the PersistentVolumeClaim of the workspace, which is ReadWriteOnce by
+// default
+func (w *Workspace) GetAccessMode() string {
+	if w.AccessMode == "" {
+		return workspaceAccessModes[0]
+	}
+
+	return w.AccessMode
+}
+
+// validateWorkspace checks the workspace of the action. Since the workspace is created in the namespace of the
+// job-executor-service jobs, tasks of an action with a workspace can't use a custom namespace
+func (a *Action) validateWorkspace() error {
+	if a.Workspace == nil {
+		return nil
+	}
+
+	if a.Workspace.Size == "" {
+		return fmt.Errorf("workspace requires size")
+	}
+
+	mountPath := a.Workspace.GetMountPath()
+	if !path.IsAbs(mountPath) {
+		return fmt.Errorf("mountPath %s of workspace must be an absolute path", mountPath)
+	}
+
+	isValidAccessMode := false
+	for _, accessMode := range workspaceAccessModes {
+		isValidAccessMode = isValidAccessMode || a.Workspace.GetAccessMode() == accessMode
+	}
+	if !isValidAccessMode {
+		return fmt.Errorf("unknown accessMode %s of workspace", a.Workspace.AccessMode)
+	}
+
+	for _, task := range a.AllTasks() {
+		if task.Namespace != "" {
+			return fmt.Errorf("task %s can't use a custom namespace in an action with a workspace", task.Name)
+		}
+
+		if task.Volume != nil && task.Volume.MountPath != "" && path.Clean(task.Volume.MountPath) == path.Clean(mountPath) {
+			return fmt.Errorf("task %s can't mount its volume at the mountPath of the workspace", task.Name)
+		}
+
+		for _, volume := range task.Volumes {
+			if path.Clean(volume.MountPath) == path.Clean(mountPath) {
+				return fmt.Errorf("task %s can't mount a volume at the mountPath of the workspace", task.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkspace(t *testing.T) {
+	configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and test"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    workspace:
+      size: "1Gi"
+      storageClassName: "standard"
+    tasks:
+      - name: "Build"
+        image: "golang"
+`
+
+	config, err := NewConfig([]byte(configYaml))
+	require.NoError(t, err)
+
+	found, action := config.FindActionByName("Build and test")
+	require.True(t, found)
+	require.NotNil(t, action.Workspace)
+	assert.Equal(t, "1Gi", action.Workspace.Size)
+	assert.Equal(t, "standard", action.Workspace.StorageClassName)
+	assert.Equal(t, "/workspace", action.Workspace.GetMountPath())
+	assert.Equal(t, "ReadWriteOnce", action.Workspace.GetAccessMode())
+
+	workspace := Workspace{Size: "1Gi", MountPath: "/data", AccessMode: "ReadWriteMany"}
+	assert.Equal(t, "/data", workspace.GetMountPath())
+	assert.Equal(t, "ReadWriteMany", workspace.GetAccessMode())
+}
+
+func TestInvalidWorkspace(t *testing.T) {
+	tests := []struct {
+		name          string
+		actionYaml    string
+		expectedError string
+	}{
+		{
+			name: "missing size",
+			actionYaml: `
+    workspace:
+      mountPath: "/data"`,
+			expectedError: "workspace requires size",
+		},
+		{
+			name: "relative mount path",
+			actionYaml: `
+    workspace:
+      size: "1Gi"
+      mountPath: "data"`,
+			expectedError: "mountPath data of workspace must be an absolute path",
+		},
+		{
+			name: "unknown access mode",
+			actionYaml: `
+    workspace:
+      size: "1Gi"
+      accessMode: "ReadOnlyMany"`,
+			expectedError: "unknown accessMode ReadOnlyMany of workspace",
+		},
+		{
+			name: "task with custom namespace",
+			actionYaml: `
+    workspace:
+      size: "1Gi"
+    finally:
+      - name: "Cleanup"
+        image: "alpine"
+        namespace: "other"`,
+			expectedError: "task Cleanup can't use a custom namespace in an action with a workspace",
+		},
+		{
+			name: "task volume at workspace mount path",
+			actionYaml: `
+    workspace:
+      size: "1Gi"
+    finally:
+      - name: "Cleanup"
+        image: "alpine"
+        volume:
+          mountPath: "/workspace/"`,
+			expectedError: "task Cleanup can't mount its volume at the mountPath of the workspace",
+		},
+		{
+			name: "task volumes at workspace mount path",
+			actionYaml: `
+    workspace:
+      size: "1Gi"
+      mountPath: "/data"
+    finally:
+      - name: "Cleanup"
+        image: "alpine"
+        volumes:
+          - mountPath: "/data"
+            configMap:
+              name: "cleanup-config"`,
+			expectedError: "task Cleanup can't mount a volume at the mountPath of the workspace",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configYaml := `
+apiVersion: v2
+actions:
+  - name: "Build and test"
+    events:
+      - name: "sh.keptn.event.test.triggered"
+    tasks:
+      - name: "Build"
+        image: "golang"` + test.actionYaml
+
+			config, err := NewConfig([]byte(configYaml))
+			assert.ErrorContains(t, err, "invalid action Build and test: "+test.expectedError)
+			assert.Nil(t, config)
+		})
+	}
+}
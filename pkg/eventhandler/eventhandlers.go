@@ -1,11 +1,11 @@
 package eventhandler
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/keptn/go-utils/pkg/sdk"
 	keptn_interface "keptn-contrib/job-executor-service/pkg/keptn"
 	"log"
-	"strconv"
 	"strings"
 	"time"
 
@@ -59,6 +59,12 @@ type K8s interface {
 	) error
 	GetFailedEventsForJob(jobName string, namespace string) (string, error)
 	GetLogsOfPod(jobName string, namespace string) (string, error)
+	GetJobContainerTermination(jobName string, namespace string) (*k8sutils.JobContainerTermination, error)
+	CreateWorkspace(
+		name string, action *config.Action, actionIndex int, jobSettings k8sutils.JobSettings,
+		jsonEventData interface{}, namespace string,
+	) error
+	DeleteWorkspace(name string, namespace string) error
 }
 
 // EventHandler contains all information needed to process an event
@@ -75,11 +81,23 @@ type EventHandler struct {
 type jobLogs struct {
 	name string
 	logs string
+
+	// skipReason is set if the task was skipped instead of being executed
+	skipReason string
+
+	// allowedFailure is set if the task failed, but is allowed to fail
+	allowedFailure error
+
+	// warning is set if the exit code of the task is mapped to a warning
+	warning error
 }
 
 type dataForFinishedEvent struct {
 	start time.Time
 	end   time.Time
+
+	// publishedResult contains the merged data that the tasks have published for the finished event
+	publishedResult map[string]interface{}
 }
 
 // Execute handles all events in a generic manner
@@ -145,7 +163,7 @@ func (eh *EventHandler) Execute(k sdk.IKeptn, event sdk.KeptnEvent) (interface{}
 }
 
 func (eh *EventHandler) startK8sJob(k sdk.IKeptn, event sdk.KeptnEvent, eventData keptn.EventProperties, action *config.Action, actionIndex int, configHash string, gitCommitID string,
-	jsonEventData interface{},
+	jsonEventData map[string]interface{},
 ) (interface{}, *sdk.Error) {
 	err := eh.K8s.ConnectToCluster()
 	if err != nil {
@@ -161,11 +179,14 @@ func (eh *EventHandler) startK8sJob(k sdk.IKeptn, event sdk.KeptnEvent, eventDat
 		start: time.Now(),
 	}
 
-	// To execute all tasks atomically, we check all images
-	// before we start executing a single task of a job
-	for _, task := range action.Tasks {
-		if !eh.ImageFilter.IsImageAllowed(task.Image) {
-			errorText := fmt.Sprintf("Forbidden: Image %s does not match configured image allowlist.\n", task.Image)
+	// The templated fields of all tasks are rendered against the event before the images are checked, such that the
+	// allowlist applies to the images that are actually used
+	allTasks := action.AllTasks()
+	tasks := make([]config.Task, len(allTasks))
+	for index, task := range allTasks {
+		tasks[index], err = task.Render(jsonEventData)
+		if err != nil {
+			errorText := fmt.Sprintf("Error while rendering task %s: %s", task.Name, err.Error())
 
 			k.Logger().Infof(errorText)
 			if !action.Silent {
@@ -176,74 +197,91 @@ func (eh *EventHandler) startK8sJob(k sdk.IKeptn, event sdk.KeptnEvent, eventDat
 		}
 	}
 
-	for index, task := range action.Tasks {
-		k.Logger().Infof("Starting task %s/%s: '%s' ...", strconv.Itoa(index+1), strconv.Itoa(len(action.Tasks)), task.Name)
-
-		// k8s job name max length is 63 characters, with the naming scheme below up to 999 tasks per action are supported
-		// the naming scheme is also unique if multiple actions in one cloud event are executed
-		jobName := fmt.Sprintf("job-executor-service-job-%s-%03d-%03d", event.ID[:24], actionIndex, index+1)
-
-		namespace := eh.JobSettings.JobNamespace
+	// To execute all tasks atomically, we check all images
+	// before we start executing a single task of a job
+	for _, task := range tasks {
+		if !eh.ImageFilter.IsImageAllowed(task.Image) {
+			errorText := fmt.Sprintf("Forbidden: Image %s does not match configured image allowlist.\n", task.Image)
 
-		if len(task.Namespace) > 0 {
-			namespace = task.Namespace
-		}
+			k.Logger().Infof(errorText)
+			if !action.Silent {
+				return nil, &sdk.Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: errorText}
+			}
 
-		jobDetails := k8sutils.JobDetails{
-			Action:        action,
-			Task:          &task,
-			ActionIndex:   actionIndex,
-			TaskIndex:     index,
-			JobConfigHash: configHash,
-			GitCommitID:   gitCommitID,
+			return nil, nil
 		}
+	}
 
-		err = eh.K8s.CreateK8sJob(
-			jobName, jobDetails, eventData, eh.JobSettings, jsonEventData, namespace,
-		)
+	// The workspace is shared by all tasks and removed after the tasks and cleanup tasks of the action have finished
+	workspaceClaimName := ""
+	if action.Workspace != nil {
+		workspaceClaimName = getWorkspaceName(event.ID, actionIndex)
 
+		err = eh.K8s.CreateWorkspace(workspaceClaimName, action, actionIndex, eh.JobSettings, jsonEventData, eh.JobSettings.JobNamespace)
 		if err != nil {
-			k.Logger().Infof("Error while creating job: %s\n", err)
+			errorText := fmt.Sprintf("Error while creating workspace: %s", err.Error())
+
+			k.Logger().Infof(errorText)
 			if !action.Silent {
-				return nil, &sdk.Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: fmt.Sprintf("Error while creating job: %s", err)}
+				return nil, &sdk.Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: errorText}
 			}
-		}
 
-		maxPollDuration := defaultMaxPollDuration
-		if task.MaxPollDuration != nil {
-			maxPollDuration = time.Duration(*task.MaxPollDuration) * time.Second
+			return nil, nil
 		}
-		jobErr := eh.K8s.AwaitK8sJobDone(jobName, maxPollDuration, pollInterval, namespace)
 
-		logs, err := eh.K8s.GetLogsOfPod(jobName, namespace)
-		if err != nil {
-			k.Logger().Infof("Error while retrieving logs: %s\n", err.Error())
-		}
+		defer func() {
+			if err := eh.K8s.DeleteWorkspace(workspaceClaimName, eh.JobSettings.JobNamespace); err != nil {
+				k.Logger().Infof("Error while deleting workspace %s: %s", workspaceClaimName, err.Error())
+			}
+		}()
+	}
 
-		if jobErr != nil {
-			k.Logger().Infof("Error while creating job: %s\n", jobErr.Error())
+	run := &actionRun{
+		k:             k,
+		event:         event,
+		eventData:     eventData,
+		action:        action,
+		actionIndex:   actionIndex,
+		configHash:    configHash,
+		gitCommitID:   gitCommitID,
+		jsonEventData: jsonEventData,
+
+		workspaceClaimName: workspaceClaimName,
+	}
 
-			erroredEventMessages, eventErr := eh.K8s.GetFailedEventsForJob(jobName, namespace)
+	// The results are in the order of the tasks, even if the tasks were executed in parallel
+	results := eh.runTasks(run, tasks[:len(action.Tasks)])
 
-			if eventErr != nil {
-				k.Logger().Infof("Error while retrieving events: %s\n", eventErr.Error())
-			} else if erroredEventMessages != "" {
-				// Found some failed events for this job - appending them to logs
-				logs = logs + "\n" + erroredEventMessages
-			}
+	// Cleanup tasks also run if a task failed, their results are appended such that a failure of a cleanup task
+	// doesn't mask the original failure of the action
+	results = append(results, eh.runCleanupTasks(run, tasks, results)...)
 
+	for _, result := range results {
+		if result.status == taskFailed {
 			if !action.Silent {
-				return nil, &sdk.Error{Err: err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: fmt.Sprintf("Error while creating job: %s", jobErr.Error())}
+				return nil, &sdk.Error{Err: result.err, StatusType: keptnv2.StatusErrored, ResultType: keptnv2.ResultFailed, Message: getTaskFailedMessage(results)}
 			}
 			return nil, nil
 		}
+	}
 
-		allJobLogs = append(
-			allJobLogs, jobLogs{
-				name: task.Name,
-				logs: logs,
-			},
-		)
+	additionalFinishedEventData.publishedResult = map[string]interface{}{}
+	for _, result := range results {
+		config.MergeResults(additionalFinishedEventData.publishedResult, result.publishedResult)
+
+		taskLogs := jobLogs{
+			name:       result.name,
+			logs:       result.logs,
+			skipReason: result.skipReason,
+		}
+
+		if result.status == taskWarning {
+			taskLogs.allowedFailure = result.err
+		}
+
+		taskLogs.warning = result.warning
+
+		allJobLogs = append(allJobLogs, taskLogs)
 	}
 
 	additionalFinishedEventData.end = time.Now()
@@ -298,7 +336,31 @@ func sendJobFailedEvent(myKeptn *keptnv2.Keptn, jobName string, serviceName stri
 func getTaskFinishedEvent(event sdk.KeptnEvent, receivedEventData keptn.EventProperties, jobLogs []jobLogs, data dataForFinishedEvent) interface{} {
 	var logMessage strings.Builder
 
+	// Tasks that are allowed to fail don't fail the action, but turn the result into a warning
+	result := keptnv2.ResultPass
+
 	for _, jobLogs := range jobLogs {
+		if jobLogs.skipReason != "" {
+			logMessage.WriteString(fmt.Sprintf("Task '%s' was skipped because %s\n\n", jobLogs.name, jobLogs.skipReason))
+			continue
+		}
+
+		if jobLogs.warning != nil {
+			result = keptnv2.ResultWarning
+			logMessage.WriteString(
+				fmt.Sprintf("Task '%s' finished with a warning: %s\n\nLogs:\n%s\n\n", jobLogs.name, jobLogs.warning.Error(), jobLogs.logs),
+			)
+			continue
+		}
+
+		if jobLogs.allowedFailure != nil {
+			result = keptnv2.ResultWarning
+			logMessage.WriteString(
+				fmt.Sprintf("Task '%s' failed, but is allowed to fail: %s\n\nLogs:\n%s\n\n", jobLogs.name, jobLogs.allowedFailure.Error(), jobLogs.logs),
+			)
+			continue
+		}
+
 		logMessage.WriteString(
 			fmt.Sprintf("Task '%s' finished successfully!\n\nLogs:\n%s\n\n", jobLogs.name, jobLogs.logs),
 		)
@@ -306,24 +368,49 @@ func getTaskFinishedEvent(event sdk.KeptnEvent, receivedEventData keptn.EventPro
 
 	eventData := &keptnv2.EventData{
 		Status:  keptnv2.StatusSucceeded,
-		Result:  keptnv2.ResultPass,
+		Result:  result,
 		Message: logMessage.String(),
 		Project: receivedEventData.GetProject(),
 		Stage:   receivedEventData.GetStage(),
 		Service: receivedEventData.GetService(),
 	}
 
+	var finishedEventData interface{} = eventData
 	if isTestTriggeredEvent(*event.Type) && !data.start.IsZero() && !data.end.IsZero() {
-		return keptnv2.TestFinishedEventData{
+		finishedEventData = keptnv2.TestFinishedEventData{
 			Test: keptnv2.TestFinishedDetails{
 				Start: data.start.Format(time.RFC3339),
 				End:   data.end.Format(time.RFC3339),
 			},
 			EventData: *eventData,
 		}
-	} else {
-		return eventData
 	}
+
+	if len(data.publishedResult) == 0 {
+		return finishedEventData
+	}
+
+	return mergePublishedResult(finishedEventData, data.publishedResult)
+}
+
+// mergePublishedResult converts the finished event data into a generic map and merges the data published by the
+// tasks into it. If the event data can't be converted, the data is returned without the published data
+func mergePublishedResult(finishedEventData interface{}, publishedResult map[string]interface{}) interface{} {
+	eventDataJSON, err := json.Marshal(finishedEventData)
+	if err != nil {
+		log.Printf("Unable to merge published result into finished event: %s", err.Error())
+		return finishedEventData
+	}
+
+	mergedEventData := map[string]interface{}{}
+	if err := json.Unmarshal(eventDataJSON, &mergedEventData); err != nil {
+		log.Printf("Unable to merge published result into finished event: %s", err.Error())
+		return finishedEventData
+	}
+
+	config.MergeResults(mergedEventData, publishedResult)
+
+	return mergedEventData
 }
 
 func isTestTriggeredEvent(eventName string) bool {
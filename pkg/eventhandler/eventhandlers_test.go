@@ -12,6 +12,7 @@ import (
 	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
 	keptnfake "github.com/keptn/go-utils/pkg/lib/v0_2_0/fake"
 	"github.com/keptn/go-utils/pkg/sdk"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"io/ioutil"
 	"keptn-contrib/job-executor-service/pkg/config"
@@ -547,6 +548,76 @@ func TestStartK8s(t *testing.T) {
 	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
 }
 
+func TestStartK8sWithTemplatedTask(t *testing.T) {
+	k8sMock := createK8sMock(t)
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockFilter := eventhandlerfake.NewMockImageFilter(mockCtrl)
+	mockJobConfigReader := eventhandlerfake.NewMockJobConfigReader(mockCtrl)
+	mockUniformErrorSender := eventhandlerfake.NewMockErrorLogSender(mockCtrl)
+
+	action := config.Action{
+		Name: "Run locust",
+		Tasks: []config.Task{
+			{
+				Name:      "Run locust tests for service",
+				Templated: true,
+				Image:     "registry.example.com/{{ .data.project }}/locust:{{ .data.labels.buildId }}",
+				Args:      []string{"--stage", "{{ .data.stage | upper }}"},
+			},
+		},
+		Events: []config.Event{
+			{
+				Name: "sh.keptn.event.action.triggered",
+			},
+		},
+	}
+
+	mockJobConfigReader.EXPECT().GetJobConfig("").Return(
+		&config.Config{
+			Actions: []config.Action{action},
+		}, "", nil,
+	).Times(1)
+
+	eh := EventHandler{
+		JobConfigReader: mockJobConfigReader,
+		ServiceName:     "test-jes",
+		JobSettings:     k8sutils.JobSettings{},
+		ImageFilter:     mockFilter,
+		Mapper:          new(KeptnCloudEventMapper),
+		K8s:             k8sMock,
+		ErrorSender:     mockUniformErrorSender,
+	}
+
+	renderedTask := action.Tasks[0]
+	renderedTask.Image = "registry.example.com/sockshop/locust:build-17"
+	renderedTask.Args = []string{"--stage", "DEV"}
+
+	mockFilter.EXPECT().IsImageAllowed("registry.example.com/sockshop/locust:build-17").Return(true).Times(1)
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName1), gomock.Eq(k8sutils.JobDetails{
+			Action:        &action,
+			Task:          &renderedTask,
+			ActionIndex:   0,
+			TaskIndex:     0,
+			JobConfigHash: "",
+		}), gomock.Any(), gomock.Any(),
+		gomock.Any(), gomock.Any(),
+	).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), defaultMaxPollDuration, pollInterval, gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", &eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 0, "sh.keptn.event.action.started")
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+}
+
 func TestStartK8sJobSilent(t *testing.T) {
 	k8sMock := createK8sMock(t)
 	mockCtrl := gomock.NewController(t)
@@ -766,4 +837,1027 @@ func checkFinishedEventImageNotAllowed(ce models.KeptnContextExtendedCE) bool {
 	}
 
 	return true
-}
\ No newline at end of file
+}
+
+const jobName3 = "job-executor-service-job-f2b878d3-03c0-4e8f-bc3f--000-003"
+
+// newActionEventHandler creates an EventHandler that uses the given K8s mock and returns a job config that only
+// contains the given action
+func newActionEventHandler(mockCtrl *gomock.Controller, k8sMock *eventhandlerfake.MockK8s, action config.Action) *EventHandler {
+	mockJobConfigReader := eventhandlerfake.NewMockJobConfigReader(mockCtrl)
+	mockJobConfigReader.EXPECT().GetJobConfig("").Return(
+		&config.Config{
+			Actions: []config.Action{action},
+		}, "", nil,
+	).Times(1)
+
+	return &EventHandler{
+		ServiceName:     "job-executor-service",
+		ImageFilter:     acceptAllImagesFilter{},
+		JobConfigReader: mockJobConfigReader,
+		Mapper:          new(KeptnCloudEventMapper),
+		K8s:             k8sMock,
+		ErrorSender:     eventhandlerfake.NewMockErrorLogSender(mockCtrl),
+		JobSettings:     k8sutils.JobSettings{},
+	}
+}
+
+// checkFinishedEventData returns a function that checks the finished event with the given check function
+func checkFinishedEventData(t *testing.T, check func(t *testing.T, eventData keptnv2.EventData)) func(ce models.KeptnContextExtendedCE) bool {
+	return func(ce models.KeptnContextExtendedCE) bool {
+		eventData := keptnv2.EventData{}
+		require.NoError(t, ce.DataAs(&eventData))
+		check(t, eventData)
+		return true
+	}
+}
+
+func TestStartK8sParallel(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run checks",
+		Tasks: []config.Task{
+			{Name: "Lint"},
+			{Name: "Unit tests"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+		Parallel: true,
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	// The first job can only finish after the second job was created, which only works if the tasks run in parallel
+	secondJobCreated := make(chan struct{})
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Do(func(string, k8sutils.JobDetails, keptn.EventProperties, k8sutils.JobSettings, interface{}, string) {
+		close(secondJobCreated)
+	}).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(string, time.Duration, time.Duration, string) error {
+			select {
+			case <-secondJobCreated:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("second job was not created in parallel")
+			}
+		},
+	).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Return("lint logs", nil).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName2), gomock.Any()).Return("unit test logs", nil).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusSucceeded, eventData.Status)
+
+		// The logs are collected in the order of the tasks
+		lintLogs := strings.Index(eventData.Message, "lint logs")
+		unitTestLogs := strings.Index(eventData.Message, "unit test logs")
+		assert.True(t, lintLogs >= 0 && unitTestLogs > lintLogs, "unexpected message: %s", eventData.Message)
+	}))
+}
+
+func TestStartK8sFailureStrategy(t *testing.T) {
+	tests := []struct {
+		name            string
+		failureStrategy string
+		expectThirdJob  bool
+	}{
+		{
+			name:            "fail fast",
+			failureStrategy: config.ActionFailureStrategyFailFast,
+			expectThirdJob:  false,
+		},
+		{
+			name:            "wait for all",
+			failureStrategy: config.ActionFailureStrategyWaitForAll,
+			expectThirdJob:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+			action := config.Action{
+				Name: "Run checks",
+				Tasks: []config.Task{
+					{Name: "Lint"},
+					{Name: "Unit tests"},
+					{Name: "SAST"},
+				},
+				Events: []config.Event{
+					{Name: "sh.keptn.event.action.triggered"},
+				},
+				Parallelism:     2,
+				FailureStrategy: test.failureStrategy,
+			}
+
+			eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+			jobNames := []string{jobName1, jobName2}
+			if test.expectThirdJob {
+				jobNames = append(jobNames, jobName3)
+			}
+
+			// The second task keeps its slot until the third task was created or a timeout occurred, such that the
+			// third task can only be started in the slot of the failed first task
+			thirdJobCreated := make(chan struct{})
+
+			k8sMock.EXPECT().ConnectToCluster().Times(1)
+			for _, jobName := range jobNames {
+				createCall := k8sMock.EXPECT().CreateK8sJob(
+					gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				).Times(1)
+				if jobName == jobName3 {
+					createCall.Do(func(string, k8sutils.JobDetails, keptn.EventProperties, k8sutils.JobSettings, interface{}, string) {
+						close(thirdJobCreated)
+					})
+				}
+				k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+			}
+
+			k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+				errors.New("job failed"),
+			).Times(1)
+			k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(string, time.Duration, time.Duration, string) {
+					select {
+					case <-thirdJobCreated:
+					case <-time.After(100 * time.Millisecond):
+					}
+				},
+			).Times(1)
+			if test.expectThirdJob {
+				k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName3), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+			}
+			k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName1), gomock.Any()).Times(1)
+
+			fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+			fakeKeptn.AddTaskHandler("*", eh)
+
+			err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+			require.NoError(t, err)
+
+			fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+			fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+				assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+				assert.Equal(t, keptnv2.ResultFailed, eventData.Result)
+				assert.Contains(t, eventData.Message, "job failed")
+			}))
+		})
+	}
+}
+
+func TestStartK8sTaskDependencies(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	const jobName4 = "job-executor-service-job-f2b878d3-03c0-4e8f-bc3f--000-004"
+	const jobName5 = "job-executor-service-job-f2b878d3-03c0-4e8f-bc3f--000-005"
+
+	action := config.Action{
+		Name: "Build and deploy",
+		Tasks: []config.Task{
+			{Name: "Build"},
+			{Name: "Unit tests", DependsOn: []string{"Build"}},
+			{Name: "Lint", DependsOn: []string{"Build"}},
+			{Name: "Deploy", DependsOn: []string{"Unit tests", "Lint"}},
+			{Name: "Notify", DependsOn: []string{"Deploy"}},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+		FailureStrategy: config.ActionFailureStrategyWaitForAll,
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+
+	// The unit tests and the linting only start after the build finished
+	awaitBuild := k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	for _, jobName := range []string{jobName1, jobName2, jobName3} {
+		createCall := k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		if jobName != jobName1 {
+			createCall.After(awaitBuild)
+		}
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("unit tests failed"),
+	).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName3), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName2), gomock.Any()).Times(1)
+
+	// Deploy and Notify must never be created, because they depend on the failed unit tests
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName4), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(0)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName5), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(0)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, "Error while creating job: unit tests failed\n"+
+			"Task 'Deploy' was skipped because task 'Unit tests' failed\n"+
+			"Task 'Notify' was skipped because task 'Unit tests' failed", eventData.Message)
+	}))
+}
+
+func TestStartK8sConditionalTasks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	const jobName4 = "job-executor-service-job-f2b878d3-03c0-4e8f-bc3f--000-004"
+	const jobName5 = "job-executor-service-job-f2b878d3-03c0-4e8f-bc3f--000-005"
+
+	action := config.Action{
+		Name: "Deploy",
+		Tasks: []config.Task{
+			{Name: "Deploy"},
+			{Name: "Notify on failure", If: "failure()"},
+			{Name: "Smoke tests"},
+			{Name: "Cleanup", If: "always()"},
+			{Name: "Report to owner", If: `$.data.labels.owner == "JohnDoe"`},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName2, jobName4} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("deployment failed"),
+	).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName1), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName4), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName3), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(0)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName5), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(0)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, "Error while creating job: deployment failed\n"+
+			"Task 'Smoke tests' was skipped because task 'Deploy' failed\n"+
+			"Task 'Report to owner' was skipped because the condition of task 'Report to owner' was not met", eventData.Message)
+	}))
+}
+
+func TestStartK8sConditionalTasksSucceeded(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Deploy",
+		Tasks: []config.Task{
+			{Name: "Deploy"},
+			{Name: "Report to owner", If: `$.data.labels.owner == "JohnDoe"`},
+			{Name: "Notify on failure", If: "failure()"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName2} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusSucceeded, eventData.Status)
+		assert.Contains(t, eventData.Message, "Task 'Report to owner' finished successfully!")
+		assert.Contains(t, eventData.Message, "Task 'Notify on failure' was skipped because the condition of task 'Notify on failure' was not met")
+	}))
+}
+
+func TestGetJobName(t *testing.T) {
+	eventID := "f2b878d3-03c0-4e8f-bc3f-454bc1b3d79b"
+
+	assert.Equal(t, jobName1, getJobName(eventID, 0, 0, 0, 1))
+	assert.Equal(t, "job-executor-service-job-f2b878d3-03c0-4e8f-b-000-002-2", getJobName(eventID, 0, 1, 0, 2))
+	assert.Equal(t, "job-executor-service-job-f2b878d3-03c0-4e-000-001-002-3", getJobName(eventID, 0, 0, 2, 3))
+
+	// The init container is named "init-" + job name, which must be a valid DNS-1123 label of at most 63 characters
+	for _, element := range []int{0, 1, 999} {
+		for _, attempt := range []int{1, 2, 10, 999} {
+			name := getJobName(eventID, 999, 998, element, attempt)
+			assert.LessOrEqual(t, len(name), 58, name)
+		}
+	}
+}
+
+func TestStartK8sRetries(t *testing.T) {
+	tests := []struct {
+		name            string
+		attemptErrors   []error
+		expectedStatus  keptnv2.StatusType
+		expectedMessage []string
+	}{
+		{
+			name:           "second attempt succeeds",
+			attemptErrors:  []error{errors.New("job failed"), nil},
+			expectedStatus: keptnv2.StatusSucceeded,
+			expectedMessage: []string{
+				"Attempt 1/3:\nlogs of attempt 1\n",
+				"Attempt 2/3:\nlogs of attempt 2\n",
+			},
+		},
+		{
+			name:            "all attempts fail",
+			attemptErrors:   []error{errors.New("job failed"), errors.New("job failed"), errors.New("job failed")},
+			expectedStatus:  keptnv2.StatusErrored,
+			expectedMessage: []string{"Error while creating job: job failed (attempt 3/3)"},
+		},
+		{
+			name:            "max poll duration exceeded",
+			attemptErrors:   []error{fmt.Errorf("polling timed out: %w", k8sutils.ErrMaxPollTimeExceeded)},
+			expectedStatus:  keptnv2.StatusErrored,
+			expectedMessage: []string{"Error while creating job: polling timed out: max poll count reached for job (attempt 1/3)"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+			action := config.Action{
+				Name: "Run integration tests",
+				Tasks: []config.Task{
+					{Name: "Integration tests", Retries: 2, RetryBackoff: "1ms"},
+				},
+				Events: []config.Event{
+					{Name: "sh.keptn.event.action.triggered"},
+				},
+			}
+
+			eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+			k8sMock.EXPECT().ConnectToCluster().Times(1)
+
+			var previousAttempt *gomock.Call
+			for index, attemptErr := range test.attemptErrors {
+				jobName := getJobName("f2b878d3-03c0-4e8f-bc3f-454bc1b3d79b", 0, 0, 0, index+1)
+
+				createCall := k8sMock.EXPECT().CreateK8sJob(
+					gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				).Times(1)
+				if previousAttempt != nil {
+					createCall.After(previousAttempt)
+				}
+
+				k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any()).Return(attemptErr).Times(1)
+				previousAttempt = k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Return(
+					fmt.Sprintf("logs of attempt %d", index+1), nil,
+				).Times(1)
+
+				if attemptErr != nil {
+					k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName), gomock.Any()).Times(1)
+				}
+			}
+
+			fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+			fakeKeptn.AddTaskHandler("*", eh)
+
+			err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+			require.NoError(t, err)
+
+			fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+			fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+				assert.Equal(t, test.expectedStatus, eventData.Status)
+				for _, expectedMessage := range test.expectedMessage {
+					assert.Contains(t, eventData.Message, expectedMessage)
+				}
+			}))
+		})
+	}
+}
+
+func TestStartK8sAllowFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run checks",
+		Tasks: []config.Task{
+			{Name: "Security scan", AllowFailure: true},
+			{Name: "Unit tests"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName2} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("vulnerabilities found"),
+	).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Return("scan logs", nil).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName1), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName2), gomock.Any()).Return("unit test logs", nil).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusSucceeded, eventData.Status)
+		assert.Equal(t, keptnv2.ResultWarning, eventData.Result)
+		assert.Equal(t, "Task 'Security scan' failed, but is allowed to fail: vulnerabilities found\n\nLogs:\nscan logs\n\n"+
+			"Task 'Unit tests' finished successfully!\n\nLogs:\nunit test logs\n\n", eventData.Message)
+	}))
+}
+
+func TestStartK8sCleanupTasksAfterFailure(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run load tests",
+		Tasks: []config.Task{
+			{Name: "Load tests"},
+		},
+		OnFailure: []config.Task{
+			{Name: "Collect diagnostics"},
+		},
+		Finally: []config.Task{
+			{Name: "Delete test data"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName2, jobName3} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		k8sutils.ErrMaxPollTimeExceeded,
+	).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName1), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName3), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("cleanup failed"),
+	).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName3), gomock.Any()).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, keptnv2.ResultFailed, eventData.Result)
+		assert.Equal(t, "Error while creating job: "+k8sutils.ErrMaxPollTimeExceeded.Error()+
+			"\nTask 'Collect diagnostics' finished successfully"+
+			"\nTask 'Delete test data' failed: cleanup failed", eventData.Message)
+	}))
+}
+
+func TestStartK8sCleanupTasksAfterSuccess(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run load tests",
+		Tasks: []config.Task{
+			{Name: "Load tests"},
+		},
+		OnFailure: []config.Task{
+			{Name: "Collect diagnostics"},
+		},
+		Finally: []config.Task{
+			{Name: "Delete test data"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	// The onFailure task is skipped, the finally task keeps its index in the job name
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName3} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	}
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Return("load test logs", nil).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName3), gomock.Any()).Return("cleanup logs", nil).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusSucceeded, eventData.Status)
+		assert.Equal(t, keptnv2.ResultPass, eventData.Result)
+		assert.Equal(t, "Task 'Load tests' finished successfully!\n\nLogs:\nload test logs\n\n"+
+			"Task 'Delete test data' finished successfully!\n\nLogs:\ncleanup logs\n\n", eventData.Message)
+	}))
+}
+
+func TestStartK8sMatrix(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run UI tests",
+		Tasks: []config.Task{
+			{
+				Name:   "UI tests",
+				Env:    []config.Env{{Name: "HEADLESS", Value: "true", ValueFrom: "string"}},
+				Matrix: &config.Matrix{Env: "BROWSER", Values: []string{"chrome", "firefox"}},
+			},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	jobNames := map[string]string{
+		"chrome":  "job-executor-service-job-f2b878d3-03c0-4e8f-b-000-001-001",
+		"firefox": "job-executor-service-job-f2b878d3-03c0-4e8f-b-000-001-002",
+	}
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for browser, jobName := range jobNames {
+		expectedEnv := []config.Env{
+			{Name: "HEADLESS", Value: "true", ValueFrom: "string"},
+			{Name: "BROWSER", Value: browser, ValueFrom: "string"},
+		}
+
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Do(func(_ string, jobDetails k8sutils.JobDetails, _ interface{}, _ interface{}, _ interface{}, _ interface{}) {
+			assert.Equal(t, expectedEnv, jobDetails.Task.Env)
+		}).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Return(browser+" logs", nil).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobNames["chrome"]), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobNames["firefox"]), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("tests failed"),
+	).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobNames["firefox"]), gomock.Any()).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, "Error while creating job: 1/2 matrix elements failed: BROWSER=firefox: tests failed", eventData.Message)
+	}))
+}
+
+func TestStartK8sOutputs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Build and deploy",
+		Tasks: []config.Task{
+			{Name: "Build", Outputs: []string{"IMAGE_TAG"}},
+			{Name: "Deploy", Env: []config.Env{{Name: "NAMESPACE", Value: "sockshop", ValueFrom: "string"}}},
+		},
+		Finally: []config.Task{
+			{Name: "Notify"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	expectedEnv := map[string][]config.Env{
+		jobName1: nil,
+		jobName2: {
+			{Name: "IMAGE_TAG", Value: "1.2.3", ValueFrom: "string"},
+			{Name: "NAMESPACE", Value: "sockshop", ValueFrom: "string"},
+		},
+		jobName3: {
+			{Name: "IMAGE_TAG", Value: "1.2.3", ValueFrom: "string"},
+		},
+	}
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for jobName, env := range expectedEnv {
+		env := env
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Do(func(_ string, jobDetails k8sutils.JobDetails, _ interface{}, _ interface{}, _ interface{}, _ interface{}) {
+			assert.Equal(t, env, jobDetails.Task.Env)
+		}).Times(1)
+		k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	k8sMock.EXPECT().GetJobContainerTermination(gomock.Eq(jobName1), gomock.Any()).Return(
+		&k8sutils.JobContainerTermination{Message: "IMAGE_TAG=1.2.3\nUNDECLARED=value\n"}, nil,
+	).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEventStatus(t, 1, keptnv2.StatusSucceeded)
+}
+
+func TestStartK8sPublishResult(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run tests",
+		Tasks: []config.Task{
+			{Name: "Load tests", PublishResult: true},
+			{Name: "Upload report", PublishResult: true},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.test.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	for _, jobName := range []string{jobName1, jobName2} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Times(1)
+		k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+		k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName), gomock.Any()).Times(1)
+	}
+
+	k8sMock.EXPECT().GetJobContainerTermination(gomock.Eq(jobName1), gomock.Any()).Return(
+		&k8sutils.JobContainerTermination{Message: `{"loadTest": {"users": 100}, "artifactVersion": "1.2.3"}`}, nil,
+	).Times(1)
+	k8sMock.EXPECT().GetJobContainerTermination(gomock.Eq(jobName2), gomock.Any()).Return(
+		&k8sutils.JobContainerTermination{Message: `{"loadTest": {"reportURL": "https://reports/4711"}}`}, nil,
+	).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/test.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, keptnv2.GetFinishedEventType(keptnv2.TestTaskName))
+	fakeKeptn.AssertSentEvent(t, 1, func(ce models.KeptnContextExtendedCE) bool {
+		eventData := map[string]interface{}{}
+		require.NoError(t, ce.DataAs(&eventData))
+
+		assert.Equal(t, "1.2.3", eventData["artifactVersion"])
+		assert.Equal(t, "succeeded", eventData["status"])
+		assert.Equal(t, "pass", eventData["result"])
+
+		loadTest, ok := eventData["loadTest"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, float64(100), loadTest["users"])
+		assert.Equal(t, "https://reports/4711", loadTest["reportURL"])
+
+		test, ok := eventData["test"].(map[string]interface{})
+		require.True(t, ok)
+		assert.NotEmpty(t, test["start"])
+		assert.NotEmpty(t, test["end"])
+		return true
+	})
+}
+
+func TestStartK8sPublishInvalidResult(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Run tests",
+		Tasks: []config.Task{
+			{Name: "Load tests", PublishResult: true},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	k8sMock.EXPECT().CreateK8sJob(
+		gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Times(1)
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetJobContainerTermination(gomock.Eq(jobName1), gomock.Any()).Return(
+		&k8sutils.JobContainerTermination{Message: `{"project": "other-project"}`}, nil,
+	).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, "sockshop", eventData.Project)
+		assert.Equal(t, "Error while creating job: invalid /keptn/result.json: result must not contain the reserved property project", eventData.Message)
+	}))
+}
+
+func TestStartK8sResultMapping(t *testing.T) {
+	tests := []struct {
+		name            string
+		jobErr          error
+		exitCode        int32
+		expectedStatus  keptnv2.StatusType
+		expectedResult  keptnv2.ResultType
+		expectedMessage string
+	}{
+		{
+			name:            "exit code mapped to warning",
+			jobErr:          errors.New("job failed"),
+			exitCode:        2,
+			expectedStatus:  keptnv2.StatusSucceeded,
+			expectedResult:  keptnv2.ResultWarning,
+			expectedMessage: "Task 'Run linter' finished with a warning: job exited with code 2, which is mapped to warning\n\nLogs:\nlinter logs\n\n",
+		},
+		{
+			name:            "exit code mapped to pass",
+			jobErr:          errors.New("job failed"),
+			exitCode:        3,
+			expectedStatus:  keptnv2.StatusSucceeded,
+			expectedResult:  keptnv2.ResultPass,
+			expectedMessage: "Task 'Run linter' finished successfully!\n\nLogs:\nlinter logs\n\n",
+		},
+		{
+			name:            "unmapped exit code",
+			jobErr:          errors.New("job failed"),
+			exitCode:        1,
+			expectedStatus:  keptnv2.StatusErrored,
+			expectedResult:  keptnv2.ResultFailed,
+			expectedMessage: "Error while creating job: job failed",
+		},
+		{
+			name:            "exit code 0 mapped to fail",
+			exitCode:        0,
+			expectedStatus:  keptnv2.StatusErrored,
+			expectedResult:  keptnv2.ResultFailed,
+			expectedMessage: "Error while creating job: job exited with code 0, which is mapped to fail",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+			action := config.Action{
+				Name: "Lint",
+				Tasks: []config.Task{
+					{
+						Name: "Run linter",
+						ResultMapping: map[int]string{
+							0: config.ResultFail, 2: config.ResultWarning, 3: config.ResultPass,
+						},
+					},
+				},
+				Events: []config.Event{
+					{Name: "sh.keptn.event.action.triggered"},
+				},
+			}
+
+			eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+			k8sMock.EXPECT().ConnectToCluster().Times(1)
+			k8sMock.EXPECT().CreateK8sJob(
+				gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+			).Times(1)
+			k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Return(test.jobErr).Times(1)
+			k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Return("linter logs", nil).Times(1)
+			k8sMock.EXPECT().GetJobContainerTermination(gomock.Eq(jobName1), gomock.Any()).Return(
+				&k8sutils.JobContainerTermination{ExitCode: test.exitCode}, nil,
+			).Times(1)
+			k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName1), gomock.Any()).AnyTimes()
+
+			fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+			fakeKeptn.AddTaskHandler("*", eh)
+
+			err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+			require.NoError(t, err)
+
+			fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+			fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+				assert.Equal(t, test.expectedStatus, eventData.Status)
+				assert.Equal(t, test.expectedResult, eventData.Result)
+				assert.Equal(t, test.expectedMessage, eventData.Message)
+			}))
+		})
+	}
+}
+
+func TestStartK8sWorkspace(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Build and test",
+		Tasks: []config.Task{
+			{Name: "Build"},
+			{Name: "Test"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+		Workspace: &config.Workspace{Size: "1Gi"},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+	eh.JobSettings.JobNamespace = "keptn"
+
+	const workspaceName = "job-executor-service-workspace-f2b878d3-03c0-4e8f-bc3f--000"
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	createWorkspace := k8sMock.EXPECT().CreateWorkspace(
+		gomock.Eq(workspaceName), gomock.Any(), gomock.Eq(0), gomock.Any(), gomock.Any(), gomock.Eq("keptn"),
+	).Times(1)
+
+	for _, jobName := range []string{jobName1, jobName2} {
+		k8sMock.EXPECT().CreateK8sJob(
+			gomock.Eq(jobName), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+		).Do(func(_ string, jobDetails k8sutils.JobDetails, _ interface{}, _ interface{}, _ interface{}, _ interface{}) {
+			assert.Equal(t, workspaceName, jobDetails.WorkspaceClaimName)
+		}).After(createWorkspace).Times(1)
+	}
+
+	k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName1), gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName1), gomock.Any()).Times(1)
+	awaitFailedJob := k8sMock.EXPECT().AwaitK8sJobDone(gomock.Eq(jobName2), gomock.Any(), gomock.Any(), gomock.Any()).Return(
+		errors.New("tests failed"),
+	).Times(1)
+	k8sMock.EXPECT().GetLogsOfPod(gomock.Eq(jobName2), gomock.Any()).Times(1)
+	k8sMock.EXPECT().GetFailedEventsForJob(gomock.Eq(jobName2), gomock.Any()).Times(1)
+
+	// The workspace is also deleted if a task failed
+	k8sMock.EXPECT().DeleteWorkspace(gomock.Eq(workspaceName), gomock.Eq("keptn")).After(awaitFailedJob).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEventStatus(t, 1, keptnv2.StatusErrored)
+}
+
+func TestStartK8sWorkspaceCreationFailed(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	k8sMock := eventhandlerfake.NewMockK8s(mockCtrl)
+
+	action := config.Action{
+		Name: "Build and test",
+		Tasks: []config.Task{
+			{Name: "Build"},
+		},
+		Events: []config.Event{
+			{Name: "sh.keptn.event.action.triggered"},
+		},
+		Workspace: &config.Workspace{Size: "1Gi"},
+	}
+
+	eh := newActionEventHandler(mockCtrl, k8sMock, action)
+
+	k8sMock.EXPECT().ConnectToCluster().Times(1)
+	k8sMock.EXPECT().CreateWorkspace(
+		gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+	).Return(errors.New("quota exceeded")).Times(1)
+
+	fakeKeptn := sdk.NewFakeKeptn("test-job-executor-service")
+	fakeKeptn.AddTaskHandler("*", eh)
+
+	err := fakeKeptn.NewEvent(newEvent("../../test/events/action.triggered.json"))
+	require.NoError(t, err)
+
+	fakeKeptn.AssertSentEventType(t, 1, "sh.keptn.event.action.finished")
+	fakeKeptn.AssertSentEvent(t, 1, checkFinishedEventData(t, func(t *testing.T, eventData keptnv2.EventData) {
+		assert.Equal(t, keptnv2.StatusErrored, eventData.Status)
+		assert.Equal(t, "Error while creating workspace: quota exceeded", eventData.Message)
+	}))
+}
@@ -195,6 +195,34 @@ func (mr *MockK8sMockRecorder) CreateK8sJob(arg0, arg1, arg2, arg3, arg4, arg5 i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateK8sJob", reflect.TypeOf((*MockK8s)(nil).CreateK8sJob), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
+// CreateWorkspace mocks base method.
+func (m *MockK8s) CreateWorkspace(arg0 string, arg1 *config.Action, arg2 int, arg3 k8sutils.JobSettings, arg4 interface{}, arg5 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockK8sMockRecorder) CreateWorkspace(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockK8s)(nil).CreateWorkspace), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// DeleteWorkspace mocks base method.
+func (m *MockK8s) DeleteWorkspace(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWorkspace", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWorkspace indicates an expected call of DeleteWorkspace.
+func (mr *MockK8sMockRecorder) DeleteWorkspace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWorkspace", reflect.TypeOf((*MockK8s)(nil).DeleteWorkspace), arg0, arg1)
+}
+
 // GetFailedEventsForJob mocks base method.
 func (m *MockK8s) GetFailedEventsForJob(arg0, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
@@ -210,6 +238,21 @@ func (mr *MockK8sMockRecorder) GetFailedEventsForJob(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedEventsForJob", reflect.TypeOf((*MockK8s)(nil).GetFailedEventsForJob), arg0, arg1)
 }
 
+// GetJobContainerTermination mocks base method.
+func (m *MockK8s) GetJobContainerTermination(arg0, arg1 string) (*k8sutils.JobContainerTermination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobContainerTermination", arg0, arg1)
+	ret0, _ := ret[0].(*k8sutils.JobContainerTermination)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobContainerTermination indicates an expected call of GetJobContainerTermination.
+func (mr *MockK8sMockRecorder) GetJobContainerTermination(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobContainerTermination", reflect.TypeOf((*MockK8s)(nil).GetJobContainerTermination), arg0, arg1)
+}
+
 // GetLogsOfPod mocks base method.
 func (m *MockK8s) GetLogsOfPod(arg0, arg1 string) (string, error) {
 	m.ctrl.T.Helper()
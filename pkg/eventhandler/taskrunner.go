@@ -0,0 +1,686 @@
+package eventhandler
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/keptn/go-utils/pkg/lib/keptn"
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/keptn/go-utils/pkg/sdk"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+	"keptn-contrib/job-executor-service/pkg/k8sutils"
+)
+
+// actionRun contains everything that is needed to run the tasks of an action for a specific event
+type actionRun struct {
+	k             sdk.IKeptn
+	event         sdk.KeptnEvent
+	eventData     keptn.EventProperties
+	action        *config.Action
+	actionIndex   int
+	configHash    string
+	gitCommitID   string
+	jsonEventData map[string]interface{}
+
+	// workspaceClaimName is the name of the PersistentVolumeClaim of the workspace of the action, if it has one
+	workspaceClaimName string
+}
+
+// taskStatus describes the state of a single task of an action
+type taskStatus int
+
+const (
+	taskPending taskStatus = iota
+	taskRunning
+	taskSucceeded
+	taskFailed
+	taskSkipped
+	// taskWarning is the status of a failed task that is allowed to fail, it is treated like a succeeded task
+	taskWarning
+)
+
+// taskResult contains the outcome of a single task of an action
+type taskResult struct {
+	name   string
+	status taskStatus
+	logs   string
+	err    error
+
+	// skipReason describes why the task was skipped, e.g. "task 'Build' failed"
+	skipReason string
+
+	// cleanup is set for onFailure and finally tasks
+	cleanup bool
+
+	// outputs contains the declared outputs the task has written to the outputs file
+	outputs map[string]string
+
+	// publishedResult contains the data the task has written to the result file
+	publishedResult map[string]interface{}
+
+	// warning is set for a succeeded task whose exit code is mapped to a warning
+	warning error
+}
+
+// jobOutcome contains the outcome of a single job of a task
+type jobOutcome struct {
+	logs            string
+	created         bool
+	outputs         map[string]string
+	publishedResult map[string]interface{}
+	warning         error
+}
+
+// finishedTask is used to report the result of a task from the goroutine that runs the task
+type finishedTask struct {
+	index  int
+	result taskResult
+}
+
+// runTasks runs the tasks of the action and returns the results in the order of the tasks. Tasks are started in the
+// order they are listed as soon as all of their dependencies succeeded and the configured parallelism allows it.
+// Tasks that depend on a failed task are skipped. If the action uses the fail fast strategy, no further tasks are
+// started after a task has failed, but tasks that are already running are awaited. Tasks with an if expression are
+// started after all preceding tasks have finished and only if their expression is met
+func (eh *EventHandler) runTasks(run *actionRun, tasks []config.Task) []taskResult {
+	results := make([]taskResult, len(tasks))
+	for index, task := range tasks {
+		results[index] = taskResult{name: task.Name, status: taskPending}
+	}
+
+	dependencies := run.action.TaskDependencies()
+	precedingTasks := run.action.PrecedingTasks()
+	maxParallelTasks := run.action.MaxParallelTasks()
+	finishedTasks := make(chan finishedTask)
+
+	running := 0
+	failedTask := ""
+	for {
+		if failedTask != "" && run.action.IsFailFast() {
+			skipTasksWithoutCondition(results, tasks, fmt.Sprintf("task '%s' failed", failedTask))
+		}
+		skipTasksWithFailedDependencies(results, tasks, dependencies)
+
+		for index, task := range tasks {
+			if running >= maxParallelTasks {
+				break
+			}
+
+			if results[index].status != taskPending {
+				continue
+			}
+
+			if task.If == "" && !areTasksSucceeded(results, dependencies[index]) {
+				continue
+			}
+
+			if task.If != "" {
+				if !areTasksFinished(results, precedingTasks[index]) {
+					continue
+				}
+
+				isMet, err := task.IsConditionMet(run.jsonEventData, isAnyTaskFailed(results, precedingTasks[index]))
+				if err != nil {
+					run.k.Logger().Infof("Unable to evaluate the condition of task %s: %s", task.Name, err.Error())
+					results[index].status = taskSkipped
+					results[index].skipReason = fmt.Sprintf("the condition of task '%s' could not be evaluated: %s", task.Name, err.Error())
+					continue
+				} else if !isMet {
+					results[index].status = taskSkipped
+					results[index].skipReason = fmt.Sprintf("the condition of task '%s' was not met", task.Name)
+					continue
+				}
+			}
+
+			results[index].status = taskRunning
+			running++
+
+			// The outputs of the preceding tasks are passed before the task is started, since the results are only
+			// modified by this loop
+			go func(index int, task config.Task) {
+				finishedTasks <- finishedTask{index: index, result: eh.runTask(run, index, len(tasks), task)}
+			}(index, withOutputs(task, results, precedingTasks[index]))
+		}
+
+		if running == 0 {
+			// Skipping tasks can make tasks with an if expression ready, which have to be checked again
+			if hasReadyConditionalTask(results, tasks, precedingTasks) {
+				continue
+			}
+			break
+		}
+
+		finished := <-finishedTasks
+		running--
+
+		results[finished.index] = finished.result
+		if finished.result.status == taskFailed && failedTask == "" {
+			failedTask = finished.result.name
+		}
+	}
+
+	// Tasks that were never started because the action failed fast are reported as skipped as well
+	for index := range results {
+		if results[index].status == taskPending {
+			results[index].status = taskSkipped
+			results[index].skipReason = fmt.Sprintf("task '%s' failed", failedTask)
+		}
+	}
+
+	return results
+}
+
+// runCleanupTasks runs the onFailure tasks of the action if one of the given results of the tasks failed and
+// afterwards the finally tasks. The given tasks contain all tasks of the action, the cleanup tasks are run one after
+// another regardless of the outcome of other cleanup tasks. If expressions of cleanup tasks are evaluated against the
+// outcome of the tasks of the action, the outputs of all tasks are passed to the cleanup tasks
+func (eh *EventHandler) runCleanupTasks(run *actionRun, tasks []config.Task, results []taskResult) []taskResult {
+	var cleanupResults []taskResult
+
+	allTasks := make([]int, len(results))
+	for index := range results {
+		allTasks[index] = index
+	}
+	actionFailed := isAnyTaskFailed(results, allTasks)
+
+	for index := len(run.action.Tasks); index < len(tasks); index++ {
+		task := withOutputs(tasks[index], results, allTasks)
+		isOnFailureTask := index < len(run.action.Tasks)+len(run.action.OnFailure)
+		if isOnFailureTask && !actionFailed {
+			continue
+		}
+
+		result := taskResult{name: task.Name, status: taskSkipped, cleanup: true}
+
+		if task.If != "" {
+			isMet, err := task.IsConditionMet(run.jsonEventData, actionFailed)
+			if err != nil {
+				run.k.Logger().Infof("Unable to evaluate the condition of task %s: %s", task.Name, err.Error())
+				result.skipReason = fmt.Sprintf("the condition of task '%s' could not be evaluated: %s", task.Name, err.Error())
+				cleanupResults = append(cleanupResults, result)
+				continue
+			} else if !isMet {
+				result.skipReason = fmt.Sprintf("the condition of task '%s' was not met", task.Name)
+				cleanupResults = append(cleanupResults, result)
+				continue
+			}
+		}
+
+		result = eh.runTask(run, index, len(tasks), task)
+		result.cleanup = true
+		cleanupResults = append(cleanupResults, result)
+	}
+
+	return cleanupResults
+}
+
+// skipTasksWithoutCondition marks all pending tasks without an if expression as skipped
+func skipTasksWithoutCondition(results []taskResult, tasks []config.Task, skipReason string) {
+	for index := range results {
+		if results[index].status == taskPending && tasks[index].If == "" {
+			results[index].status = taskSkipped
+			results[index].skipReason = skipReason
+		}
+	}
+}
+
+// skipTasksWithFailedDependencies marks all pending tasks without an if expression as skipped that depend on a failed
+// or skipped task
+func skipTasksWithFailedDependencies(results []taskResult, tasks []config.Task, dependencies [][]int) {
+	// Skipping a task can cause other tasks to be skipped, so the tasks are checked until nothing changes anymore
+	for changed := true; changed; {
+		changed = false
+		for index := range results {
+			if results[index].status != taskPending || tasks[index].If != "" {
+				continue
+			}
+
+			for _, dependency := range dependencies[index] {
+				switch results[dependency].status {
+				case taskFailed:
+					results[index].skipReason = fmt.Sprintf("task '%s' failed", results[dependency].name)
+				case taskSkipped:
+					results[index].skipReason = results[dependency].skipReason
+				default:
+					continue
+				}
+
+				results[index].status = taskSkipped
+				changed = true
+				break
+			}
+		}
+	}
+}
+
+// hasReadyConditionalTask checks if there is a pending task with an if expression whose preceding tasks are finished
+func hasReadyConditionalTask(results []taskResult, tasks []config.Task, precedingTasks [][]int) bool {
+	for index := range results {
+		if results[index].status == taskPending && tasks[index].If != "" && areTasksFinished(results, precedingTasks[index]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// areTasksSucceeded checks if all given tasks have succeeded
+func areTasksSucceeded(results []taskResult, tasks []int) bool {
+	for _, task := range tasks {
+		if results[task].status != taskSucceeded && results[task].status != taskWarning {
+			return false
+		}
+	}
+
+	return true
+}
+
+// areTasksFinished checks if all given tasks have succeeded, failed or were skipped
+func areTasksFinished(results []taskResult, tasks []int) bool {
+	for _, task := range tasks {
+		if results[task].status == taskPending || results[task].status == taskRunning {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAnyTaskFailed checks if one of the given tasks has failed
+func isAnyTaskFailed(results []taskResult, tasks []int) bool {
+	for _, task := range tasks {
+		if results[task].status == taskFailed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withOutputs returns a copy of the task with the outputs of the given tasks as environment variables, if these tasks
+// have succeeded. Outputs of later tasks replace outputs of earlier tasks with the same name and the environment
+// variables of the task itself take precedence over all outputs
+func withOutputs(task config.Task, results []taskResult, tasks []int) config.Task {
+	outputs := map[string]string{}
+	for _, index := range tasks {
+		if results[index].status != taskSucceeded && results[index].status != taskWarning {
+			continue
+		}
+
+		for name, value := range results[index].outputs {
+			outputs[name] = value
+		}
+	}
+
+	if len(outputs) == 0 {
+		return task
+	}
+
+	// The outputs are sorted to create the same job for the same outputs
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]config.Env, 0, len(outputs)+len(task.Env))
+	for _, name := range names {
+		env = append(env, config.Env{Name: name, Value: outputs[name], ValueFrom: "string"})
+	}
+	task.Env = append(env, task.Env...)
+
+	return task
+}
+
+// getTaskFailedMessage returns the message of the finished event for an action with failed tasks. It contains the
+// error of the first failed task, lists all other failed and skipped tasks and the outcome of the cleanup tasks
+func getTaskFailedMessage(results []taskResult) string {
+	var message strings.Builder
+
+	firstFailedTask := -1
+	for index, result := range results {
+		if result.status == taskFailed {
+			message.WriteString(fmt.Sprintf("Error while creating job: %s", result.err.Error()))
+			firstFailedTask = index
+			break
+		}
+	}
+
+	for index, result := range results {
+		if result.status == taskFailed && index != firstFailedTask {
+			message.WriteString(fmt.Sprintf("\nTask '%s' failed: %s", result.name, result.err.Error()))
+		}
+
+		if result.status == taskSucceeded && result.warning != nil {
+			message.WriteString(fmt.Sprintf("\nTask '%s' finished with a warning: %s", result.name, result.warning.Error()))
+		} else if result.status == taskSucceeded && result.cleanup {
+			message.WriteString(fmt.Sprintf("\nTask '%s' finished successfully", result.name))
+		}
+
+		if result.status == taskWarning {
+			message.WriteString(fmt.Sprintf("\nTask '%s' failed, but is allowed to fail: %s", result.name, result.err.Error()))
+		}
+
+		if result.status == taskSkipped {
+			message.WriteString(fmt.Sprintf("\nTask '%s' was skipped because %s", result.name, result.skipReason))
+		}
+	}
+
+	return message.String()
+}
+
+// maxMatrixElements is the maximum number of elements of a matrix that is supported by the job naming scheme
+const maxMatrixElements = 999
+
+// getJobName returns the name of the job for an attempt of a task. The name of the init container is the job name
+// prefixed with "init-", which must not exceed the 63 characters of a DNS-1123 label, so job names are at most 57
+// characters long. With the naming scheme below up to 999 tasks per action, 999 elements per matrix and 999 attempts
+// per task are supported. The naming scheme is also unique if multiple actions in one cloud event are executed. Jobs
+// of a matrix element, which is counted from 1, are suffixed with the element, attempts after the first one with the
+// number of the attempt. Every suffix shortens the part of the event ID that is used by 4 characters
+func getJobName(eventID string, actionIndex int, taskIndex int, element int, attempt int) string {
+	eventIDLength := 24
+	suffix := fmt.Sprintf("-%03d-%03d", actionIndex, taskIndex+1)
+
+	if element > 0 {
+		eventIDLength -= 4
+		suffix += fmt.Sprintf("-%03d", element)
+	}
+
+	if attempt > 1 {
+		eventIDLength -= 4
+		suffix += fmt.Sprintf("-%d", attempt)
+	}
+
+	return "job-executor-service-job-" + eventID[:eventIDLength] + suffix
+}
+
+// getWorkspaceName returns the name of the PersistentVolumeClaim of the workspace of an action, which is unique for
+// every action of a cloud event
+func getWorkspaceName(eventID string, actionIndex int) string {
+	return fmt.Sprintf("job-executor-service-workspace-%s-%03d", eventID[:24], actionIndex)
+}
+
+// runTask runs a single task, either as one job or as one job per element of its matrix. Failed tasks that are
+// allowed to fail are reported with a warning status
+func (eh *EventHandler) runTask(run *actionRun, index int, numberOfTasks int, task config.Task) taskResult {
+	run.k.Logger().Infof("Starting task %s/%s: '%s' ...", strconv.Itoa(index+1), strconv.Itoa(numberOfTasks), task.Name)
+
+	var result taskResult
+	if task.Matrix != nil {
+		result = eh.runMatrixTask(run, index, task)
+	} else {
+		result = eh.runTaskAttempts(run, index, 0, task)
+	}
+
+	if result.status == taskFailed && task.AllowFailure {
+		run.k.Logger().Infof("Task '%s' failed, but is allowed to fail: %s", task.Name, result.err.Error())
+		result.status = taskWarning
+	}
+
+	return result
+}
+
+// runMatrixTask runs the task once for every element of its matrix, with the element exposed in the environment
+// variable of the matrix. The results of all elements are aggregated into one result, which failed if any element
+// failed. Tasks with an empty matrix are skipped
+func (eh *EventHandler) runMatrixTask(run *actionRun, index int, task config.Task) taskResult {
+	result := taskResult{
+		name:   task.Name,
+		status: taskFailed,
+	}
+
+	values, err := task.Matrix.GetValues(run.jsonEventData)
+	if err != nil {
+		run.k.Logger().Infof("Unable to resolve the matrix of task %s: %s", task.Name, err.Error())
+		result.err = err
+		return result
+	}
+
+	if len(values) == 0 {
+		result.status = taskSkipped
+		result.skipReason = fmt.Sprintf("the matrix of task '%s' is empty", task.Name)
+		return result
+	}
+
+	if len(values) > maxMatrixElements {
+		result.err = fmt.Errorf("matrix has %d elements, but at most %d are supported", len(values), maxMatrixElements)
+		return result
+	}
+
+	parallelism := task.Matrix.Parallelism
+	if parallelism == 0 {
+		parallelism = len(values)
+	}
+
+	elementResults := make([]taskResult, len(values))
+	freeSlots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for element, value := range values {
+		elementTask := task
+		elementTask.Env = append(append([]config.Env{}, task.Env...), config.Env{
+			Name:      task.Matrix.Env,
+			Value:     value,
+			ValueFrom: "string",
+		})
+
+		freeSlots <- struct{}{}
+		wg.Add(1)
+		go func(element int, elementTask config.Task) {
+			defer wg.Done()
+			elementResults[element] = eh.runTaskAttempts(run, index, element+1, elementTask)
+			<-freeSlots
+		}(element, elementTask)
+	}
+	wg.Wait()
+
+	// The outputs and results of later elements replace the ones of earlier elements with the same name
+	var logs strings.Builder
+	var failedElements []string
+	var warningElements []string
+	outputs := map[string]string{}
+	publishedResult := map[string]interface{}{}
+	for element, elementResult := range elementResults {
+		logs.WriteString(fmt.Sprintf("%s=%s:\n%s\n", task.Matrix.Env, values[element], elementResult.logs))
+
+		for name, value := range elementResult.outputs {
+			outputs[name] = value
+		}
+		config.MergeResults(publishedResult, elementResult.publishedResult)
+
+		if elementResult.status == taskFailed {
+			failedElements = append(failedElements, fmt.Sprintf("%s=%s: %s", task.Matrix.Env, values[element], elementResult.err.Error()))
+		}
+
+		if elementResult.warning != nil {
+			warningElements = append(warningElements, fmt.Sprintf("%s=%s: %s", task.Matrix.Env, values[element], elementResult.warning.Error()))
+		}
+	}
+
+	result.logs = logs.String()
+	if len(failedElements) > 0 {
+		result.err = fmt.Errorf("%d/%d matrix elements failed: %s", len(failedElements), len(values), strings.Join(failedElements, ", "))
+		return result
+	}
+
+	if len(warningElements) > 0 {
+		result.warning = fmt.Errorf("%d/%d matrix elements finished with a warning: %s", len(warningElements), len(values), strings.Join(warningElements, ", "))
+	}
+
+	result.status = taskSucceeded
+	result.outputs = outputs
+	result.publishedResult = publishedResult
+	return result
+}
+
+// runTaskAttempts runs the job of a task or of an element of its matrix and retries failed jobs as often as
+// configured in the task. The logs of all attempts are collected and labeled with the number of the attempt if the
+// task can be retried
+func (eh *EventHandler) runTaskAttempts(run *actionRun, index int, element int, task config.Task) taskResult {
+	result := taskResult{
+		name:   task.Name,
+		status: taskFailed,
+	}
+
+	attempts := task.Retries + 1
+
+	var logs strings.Builder
+	for attempt := 1; attempt <= attempts; attempt++ {
+		jobName := getJobName(run.event.ID, run.actionIndex, index, element, attempt)
+
+		outcome, err := eh.runJob(run, jobName, index, task)
+		if task.Retries > 0 {
+			logs.WriteString(fmt.Sprintf("Attempt %d/%d:\n%s\n", attempt, attempts, outcome.logs))
+		} else {
+			logs.WriteString(outcome.logs)
+		}
+
+		if err == nil {
+			result.status = taskSucceeded
+			result.err = nil
+			result.outputs = outcome.outputs
+			result.publishedResult = outcome.publishedResult
+			result.warning = outcome.warning
+			break
+		}
+
+		result.err = err
+		if task.Retries > 0 {
+			result.err = fmt.Errorf("%w (attempt %d/%d)", err, attempt, attempts)
+		}
+
+		// Jobs that couldn't be created would fail again and jobs that exceeded the max poll duration are still
+		// running, so only jobs that actually failed are retried
+		if !outcome.created || errors.Is(err, k8sutils.ErrMaxPollTimeExceeded) || attempt == attempts {
+			break
+		}
+
+		run.k.Logger().Infof("Retrying task '%s' in %s (attempt %d/%d) ...", task.Name, task.GetRetryBackoff(), attempt+1, attempts)
+		time.Sleep(task.GetRetryBackoff())
+	}
+
+	result.logs = logs.String()
+	return result
+}
+
+// runJob creates the job for a task, waits until the job is done and collects the logs of the job. If the task
+// declares outputs, they are read from the termination message of a succeeded job
+func (eh *EventHandler) runJob(run *actionRun, jobName string, index int, task config.Task) (jobOutcome, error) {
+	namespace := eh.JobSettings.JobNamespace
+
+	if len(task.Namespace) > 0 {
+		namespace = task.Namespace
+	}
+
+	jobDetails := k8sutils.JobDetails{
+		Action:        run.action,
+		Task:          &task,
+		ActionIndex:   run.actionIndex,
+		TaskIndex:     index,
+		JobConfigHash: run.configHash,
+		GitCommitID:   run.gitCommitID,
+
+		WorkspaceClaimName: run.workspaceClaimName,
+	}
+
+	err := eh.K8s.CreateK8sJob(
+		jobName, jobDetails, run.eventData, eh.JobSettings, run.jsonEventData, namespace,
+	)
+
+	if err != nil {
+		run.k.Logger().Infof("Error while creating job: %s\n", err)
+		return jobOutcome{}, err
+	}
+
+	outcome := jobOutcome{created: true}
+
+	maxPollDuration := defaultMaxPollDuration
+	if task.MaxPollDuration != nil {
+		maxPollDuration = time.Duration(*task.MaxPollDuration) * time.Second
+	}
+	jobErr := eh.K8s.AwaitK8sJobDone(jobName, maxPollDuration, pollInterval, namespace)
+
+	outcome.logs, err = eh.K8s.GetLogsOfPod(jobName, namespace)
+	if err != nil {
+		run.k.Logger().Infof("Error while retrieving logs: %s\n", err.Error())
+	}
+
+	// The termination of the job container contains the exit code for the result mapping and the outputs or result of
+	// a succeeded job
+	var termination *k8sutils.JobContainerTermination
+	var terminationErr error
+	if len(task.ResultMapping) > 0 || (jobErr == nil && task.TerminationMessagePath() != "") {
+		termination, terminationErr = eh.K8s.GetJobContainerTermination(jobName, namespace)
+	}
+
+	// Jobs that timed out didn't terminate with an exit code of the job, so their result can't be mapped
+	if len(task.ResultMapping) > 0 && !errors.Is(jobErr, k8sutils.ErrMaxPollTimeExceeded) && !errors.Is(jobErr, k8sutils.ErrTaskDeadlineExceeded) {
+		if terminationErr != nil {
+			run.k.Logger().Infof("Error while retrieving exit code: %s\n", terminationErr.Error())
+		} else {
+			jobErr, outcome.warning = mapExitCode(task, termination.ExitCode, jobErr)
+		}
+	}
+
+	if jobErr != nil {
+		run.k.Logger().Infof("Error while creating job: %s\n", jobErr.Error())
+
+		erroredEventMessages, eventErr := eh.K8s.GetFailedEventsForJob(jobName, namespace)
+
+		if eventErr != nil {
+			run.k.Logger().Infof("Error while retrieving events: %s\n", eventErr.Error())
+		} else if erroredEventMessages != "" {
+			// Found some failed events for this job - appending them to logs
+			outcome.logs = outcome.logs + "\n" + erroredEventMessages
+		}
+
+		return outcome, jobErr
+	}
+
+	if len(task.Outputs) > 0 {
+		if terminationErr != nil {
+			run.k.Logger().Infof("Error while retrieving outputs: %s\n", terminationErr.Error())
+		} else {
+			outcome.outputs = task.ParseOutputs(termination.Message)
+		}
+	}
+
+	// A task that publishes an invalid result fails, since the data it should provide is missing in the event
+	if task.PublishResult {
+		if terminationErr != nil {
+			return outcome, fmt.Errorf("unable to read %s: %w", config.ResultFilePath, terminationErr)
+		}
+
+		// Events that aren't Keptn task events, e.g. custom event types, have no task payload that has to be protected
+		eventTaskName, _, _ := keptnv2.ParseTaskEventType(*run.event.Type)
+		outcome.publishedResult, err = config.ParseResult(termination.Message, eventTaskName)
+		if err != nil {
+			return outcome, fmt.Errorf("invalid %s: %w", config.ResultFilePath, err)
+		}
+	}
+
+	return outcome, nil
+}
+
+// mapExitCode maps the exit code of the job container to the result of the task. Returns the error of the job, which
+// is removed for exit codes mapped to pass or warning, and the warning of the job
+func mapExitCode(task config.Task, exitCode int32, jobErr error) (error, error) {
+	switch task.GetResultForExitCode(exitCode) {
+	case config.ResultPass:
+		return nil, nil
+	case config.ResultWarning:
+		return nil, fmt.Errorf("job exited with code %d, which is mapped to %s", exitCode, config.ResultWarning)
+	default:
+		if jobErr == nil {
+			jobErr = fmt.Errorf("job exited with code %d, which is mapped to %s", exitCode, config.ResultFail)
+		}
+		return jobErr, nil
+	}
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/spf13/afero"
 )
 
-// MountFiles requests all specified files of a task from the keptn configuration service and copies them to /keptn
-func MountFiles(actionName string, taskName string, gitCommitID string, fs afero.Fs, jcr config.JobConfigReader) error {
+// MountFiles requests all specified files of a task from the keptn configuration service and copies them to the mount
+// path of the job volume
+func MountFiles(
+	actionName string, taskName string, gitCommitID string, mountPath string, fs afero.Fs, jcr config.JobConfigReader,
+) error {
 	configuration, _, err := jcr.GetJobConfig(gitCommitID)
 	if err != nil {
 		return fmt.Errorf("could not find config for job-executor-service: %v", err)
@@ -35,9 +38,9 @@ func MountFiles(actionName string, taskName string, gitCommitID string, fs afero
 		// If the given resource is a folder, all files contained in the folder have to be copied over
 		// to the filesystem of the workload
 		for resourceURI, resourceContent := range allServiceResources {
-			// Our mount starts with /keptn
-			dir := filepath.Join("/keptn", filepath.Dir(resourceURI))
-			fullFilePath := filepath.Join("/keptn", resourceURI)
+			// Our mount starts with the mount path of the job volume
+			dir := filepath.Join(mountPath, filepath.Dir(resourceURI))
+			fullFilePath := filepath.Join(mountPath, resourceURI)
 
 			err := fs.MkdirAll(dir, 0700)
 			if err != nil {
@@ -72,7 +72,7 @@ func TestMountFiles(t *testing.T) {
 		"/helm/values.yaml",
 	).Times(1).Return(map[string][]byte{"helm/values.yaml": []byte(yamlFile)}, nil)
 
-	err := MountFiles("action", "task", "", fs, sut)
+	err := MountFiles("action", "task", "", "/keptn", fs, sut)
 	require.NoError(t, err)
 
 	exists, err := afero.Exists(fs, "/keptn/locust/basic.py")
@@ -92,6 +92,36 @@ func TestMountFiles(t *testing.T) {
 	assert.Equal(t, yamlFile, string(file))
 }
 
+func TestMountFilesCustomMountPath(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	resourceServiceMock := CreateKeptnResourceServiceMock(t)
+
+	resourceServiceMock.EXPECT().GetServiceResource("job/config.yaml", "").Return(
+		[]byte(simpleConfig),
+		nil,
+	)
+
+	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
+
+	resourceServiceMock.EXPECT().GetAllKeptnResources(
+		"locust",
+	).Times(1).Return(map[string][]byte{"locust/basic.py": []byte(pythonFile)}, nil)
+	resourceServiceMock.EXPECT().GetAllKeptnResources(
+		"/helm/values.yaml",
+	).Times(1).Return(map[string][]byte{"helm/values.yaml": []byte(yamlFile)}, nil)
+
+	err := MountFiles("action", "task", "", "/data", fs, sut)
+	require.NoError(t, err)
+
+	file, err := afero.ReadFile(fs, "/data/locust/basic.py")
+	assert.NoError(t, err)
+	assert.Equal(t, pythonFile, string(file))
+
+	exists, err := afero.Exists(fs, "/keptn/locust/basic.py")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func TestMountFilesConfigFileNotFound(t *testing.T) {
 	fs := afero.NewMemMapFs()
 	resourceServiceMock := CreateKeptnResourceServiceMock(t)
@@ -103,7 +133,7 @@ func TestMountFilesConfigFileNotFound(t *testing.T) {
 
 	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
 
-	err := MountFiles("action", "task", "", fs, sut)
+	err := MountFiles("action", "task", "", "/keptn", fs, sut)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not find config for job-executor-service")
 }
@@ -119,7 +149,7 @@ func TestMountFilesConfigFileNotValid(t *testing.T) {
 
 	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
 
-	err := MountFiles("action", "task", "", fs, sut)
+	err := MountFiles("action", "task", "", "/keptn", fs, sut)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "cannot unmarshal")
@@ -136,7 +166,7 @@ func TestMountFilesNoActionMatch(t *testing.T) {
 
 	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
 
-	err := MountFiles("actionNotMatching", "task", "", fs, sut)
+	err := MountFiles("actionNotMatching", "task", "", "/keptn", fs, sut)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no action found with name 'actionNotMatching'")
 }
@@ -152,7 +182,7 @@ func TestMountFilesNoTaskMatch(t *testing.T) {
 
 	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
 
-	err := MountFiles("action", "taskNotMatching", "", fs, sut)
+	err := MountFiles("action", "taskNotMatching", "", "/keptn", fs, sut)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no task found with name 'taskNotMatching'")
 }
@@ -172,7 +202,7 @@ func TestMountFilesFileNotFound(t *testing.T) {
 
 	sut := config2.JobConfigReader{Keptn: resourceServiceMock}
 
-	err := MountFiles("action", "task", "", fs, sut)
+	err := MountFiles("action", "task", "", "/keptn", fs, sut)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "not found")
 }
@@ -0,0 +1,62 @@
+package k8sutils
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+// CreateImagePullSecrets creates the references of the default image pull secrets of the jobs from a comma separated
+// list of secret names
+func CreateImagePullSecrets(secretNames string) []v1.LocalObjectReference {
+	var imagePullSecrets []v1.LocalObjectReference
+	for _, name := range strings.Split(secretNames, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			imagePullSecrets = append(imagePullSecrets, v1.LocalObjectReference{Name: name})
+		}
+	}
+
+	return imagePullSecrets
+}
+
+// selectImagePullSecrets returns the image pull secrets of the job. The image pull secrets of the task are added to
+// the default ones, which are still needed e.g. for the image of the init container. Since both refer to secrets in
+// the job namespace they are only used for jobs in this namespace
+func selectImagePullSecrets(
+	task *config.Task, jobSettings JobSettings, namespace string,
+) ([]v1.LocalObjectReference, error) {
+	if namespace != jobSettings.JobNamespace {
+		if len(task.ImagePullSecrets) > 0 {
+			return nil, fmt.Errorf(
+				"imagePullSecrets of task %v can only be used in the job namespace %v", task.Name,
+				jobSettings.JobNamespace,
+			)
+		}
+
+		return nil, nil
+	}
+
+	if len(task.ImagePullSecrets) == 0 {
+		return jobSettings.DefaultImagePullSecrets, nil
+	}
+
+	var imagePullSecrets []v1.LocalObjectReference
+	names := map[string]bool{}
+	for _, imagePullSecret := range jobSettings.DefaultImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, imagePullSecret)
+		names[imagePullSecret.Name] = true
+	}
+
+	for _, name := range task.ImagePullSecrets {
+		if !names[name] {
+			imagePullSecrets = append(imagePullSecrets, v1.LocalObjectReference{Name: name})
+			names[name] = true
+		}
+	}
+
+	return imagePullSecrets, nil
+}
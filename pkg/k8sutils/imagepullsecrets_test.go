@@ -0,0 +1,133 @@
+package k8sutils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+func TestCreateImagePullSecrets(t *testing.T) {
+	assert.Equal(t, []corev1.LocalObjectReference{
+		{Name: "registry-credentials"},
+		{Name: "ghcr-credentials"},
+	}, CreateImagePullSecrets("registry-credentials, ghcr-credentials,"))
+
+	assert.Nil(t, CreateImagePullSecrets(""))
+}
+
+func TestCreateK8sJobImagePullSecrets(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	tests := []struct {
+		name                     string
+		imagePullSecrets         []string
+		namespace                string
+		expectedImagePullSecrets []corev1.LocalObjectReference
+	}{
+		{
+			name:                     "default image pull secrets",
+			namespace:                testNamespace,
+			expectedImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-credentials"}},
+		},
+		{
+			name:             "image pull secrets of the task are added to the defaults",
+			imagePullSecrets: []string{"ghcr-credentials", "quay-credentials"},
+			namespace:        testNamespace,
+			expectedImagePullSecrets: []corev1.LocalObjectReference{
+				{Name: "registry-credentials"}, {Name: "ghcr-credentials"}, {Name: "quay-credentials"},
+			},
+		},
+		{
+			name:                     "image pull secrets of the task which are defaults",
+			imagePullSecrets:         []string{"ghcr-credentials", "registry-credentials"},
+			namespace:                testNamespace,
+			expectedImagePullSecrets: []corev1.LocalObjectReference{{Name: "registry-credentials"}, {Name: "ghcr-credentials"}},
+		},
+		{
+			name:      "custom namespace",
+			namespace: "other-namespace",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClientSet := k8sfake.NewSimpleClientset()
+			k8s := K8sImpl{clientset: k8sClientSet}
+
+			err := k8s.CreateK8sJob(
+				"job-with-image-pull-secrets",
+				JobDetails{
+					Action: &config.Action{Name: "Run tests"},
+					Task: &config.Task{
+						Name:             "Integration tests",
+						Image:            "registry.example.com/tests:1.0.0",
+						ImagePullSecrets: test.imagePullSecrets,
+					},
+				},
+				&eventData, JobSettings{
+					JobNamespace: testNamespace,
+					DefaultResourceRequirements: &corev1.ResourceRequirements{
+						Limits:   make(corev1.ResourceList),
+						Requests: make(corev1.ResourceList),
+					},
+					DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+					DefaultSecurityContext:    new(corev1.SecurityContext),
+					DefaultImagePullSecrets:   []corev1.LocalObjectReference{{Name: "registry-credentials"}},
+				}, eventAsInterface, test.namespace,
+			)
+			require.NoError(t, err)
+
+			job, err := k8sClientSet.BatchV1().Jobs(test.namespace).Get(
+				context.TODO(), "job-with-image-pull-secrets", metav1.GetOptions{},
+			)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedImagePullSecrets, job.Spec.Template.Spec.ImagePullSecrets)
+		})
+	}
+}
+
+func TestCreateK8sJobImagePullSecretsInCustomNamespace(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	err := k8s.CreateK8sJob(
+		"job-with-image-pull-secrets",
+		JobDetails{
+			Action: &config.Action{Name: "Run tests"},
+			Task: &config.Task{
+				Name:             "Integration tests",
+				Image:            "registry.example.com/tests:1.0.0",
+				ImagePullSecrets: []string{"ghcr-credentials"},
+			},
+		},
+		&eventData, JobSettings{JobNamespace: testNamespace}, eventAsInterface, "other-namespace",
+	)
+	assert.EqualError(
+		t, err, "imagePullSecrets of task Integration tests can only be used in the job namespace "+testNamespace,
+	)
+}
@@ -8,8 +8,10 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
 	"log"
+	"path"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -27,13 +29,13 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const envValueFromEvent = "event"
 const envValueFromSecret = "secret"
 const envValueFromString = "string"
+const envValueFromConfigMap = "configmap"
 
 const minTTLSecondsAfterFinished = int32(60)
 const defaultTTLSecondsAfterFinished = int32(21600)
@@ -61,6 +63,9 @@ type JobDetails struct {
 	TaskIndex     int
 	JobConfigHash string
 	GitCommitID   string
+
+	// WorkspaceClaimName is the name of the PersistentVolumeClaim of the workspace of the action, if it has one
+	WorkspaceClaimName string
 }
 
 // JobSettings contains environment variable settings for the job
@@ -76,6 +81,10 @@ type JobSettings struct {
 	TaskDeadlineSeconds         *int64
 	JobLabels                   map[string]string
 	JesDeploymentName           string
+	JobVolumeSettings           *JobVolumeSettings
+	AllowedVolumeSources        *utils.VolumeSourceAllowList
+	JobScheduling               *JobSchedulingSettings
+	DefaultImagePullSecrets     []v1.LocalObjectReference
 }
 
 // K8sImpl is used to interact with kubernetes jobs
@@ -111,17 +120,30 @@ func (k8s *K8sImpl) CreateK8sJob(
 
 	var backOffLimit int32 = 0
 
-	jobVolumeName := "job-volume"
+	emptyDirVolume, jobVolumeMountPath, err := createJobVolume(task, jobSettings.JobVolumeSettings)
+	if err != nil {
+		return fmt.Errorf("unable to create job volume for task %v: %v", task.Name, err.Error())
+	}
 
-	// TODO configure from outside:
-	jobVolumeMountPath := "/keptn"
+	if jobDetails.WorkspaceClaimName != "" && path.Clean(jobVolumeMountPath) == path.Clean(action.Workspace.GetMountPath()) {
+		return fmt.Errorf(
+			"job volume of task %v can't be mounted at the mount path of the workspace %v", task.Name,
+			jobVolumeMountPath,
+		)
+	}
+
+	imagePullSecrets, err := selectImagePullSecrets(task, jobSettings, namespace)
+	if err != nil {
+		return err
+	}
 
-	// TODO configure from outside:
-	quantity := resource.MustParse("20Mi")
+	taskVolumes, taskVolumeMounts, err := createTaskVolumes(task, jobVolumeMountPath, jobSettings.AllowedVolumeSources)
+	if err != nil {
+		return fmt.Errorf("unable to create volumes for task %v: %w", task.Name, err)
+	}
 
 	jobResourceRequirements := jobSettings.DefaultResourceRequirements
 	if task.Resources != nil {
-		var err error
 		jobResourceRequirements, err = CreateResourceRequirements(
 			task.Resources.Limits.CPU,
 			task.Resources.Limits.Memory,
@@ -133,11 +155,6 @@ func (k8s *K8sImpl) CreateK8sJob(
 		}
 	}
 
-	emptyDirVolume := v1.EmptyDirVolumeSource{
-		Medium:    v1.StorageMediumDefault,
-		SizeLimit: &quantity,
-	}
-
 	// Use default service account but allow overriding
 	// from the task configuration
 	serviceAccountName := jobSettings.DefaultJobServiceAccount
@@ -329,6 +346,10 @@ func (k8s *K8sImpl) CreateK8sJob(
 									Name:  "GIT_COMMIT_ID",
 									Value: jobDetails.GitCommitID,
 								},
+								{
+									Name:  "JOB_VOLUME_MOUNT_PATH",
+									Value: jobVolumeMountPath,
+								},
 							},
 							Resources: *jobSettings.DefaultResourceRequirements,
 						},
@@ -348,8 +369,9 @@ func (k8s *K8sImpl) CreateK8sJob(
 									MountPath: jobVolumeMountPath,
 								},
 							},
-							Env:       jobEnv,
-							Resources: *jobResourceRequirements,
+							Env:                    jobEnv,
+							Resources:              *jobResourceRequirements,
+							TerminationMessagePath: task.TerminationMessagePath(),
 						},
 					},
 					RestartPolicy: v1.RestartPolicyNever,
@@ -362,6 +384,7 @@ func (k8s *K8sImpl) CreateK8sJob(
 						},
 					},
 					ServiceAccountName: serviceAccountName,
+					ImagePullSecrets:   imagePullSecrets,
 				},
 			},
 			BackoffLimit:            &backOffLimit,
@@ -370,6 +393,18 @@ func (k8s *K8sImpl) CreateK8sJob(
 		},
 	}
 
+	podSpec := &jobSpec.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, taskVolumes...)
+	podSpec.Containers[0].VolumeMounts = append(podSpec.Containers[0].VolumeMounts, taskVolumeMounts...)
+
+	if err := applyScheduling(podSpec, task, jobSettings.JobScheduling); err != nil {
+		return fmt.Errorf("unable to schedule job for task %v: %w", task.Name, err)
+	}
+
+	if jobDetails.WorkspaceClaimName != "" {
+		addWorkspace(&jobSpec.Spec.Template.Spec, jobDetails.WorkspaceClaimName, action.Workspace.GetMountPath())
+	}
+
 	jobs := k8s.clientset.BatchV1().Jobs(namespace)
 
 	_, err = jobs.Create(context.TODO(), jobSpec, metav1.CreateOptions{})
@@ -478,6 +513,8 @@ func (k8s *K8sImpl) prepareJobEnv(
 			generatedEnv, err = k8s.generateEnvFromSecret(env, namespace)
 		case envValueFromString:
 			generatedEnv = generateEnvFromString(env)
+		case envValueFromConfigMap:
+			generatedEnv, err = k8s.generateEnvFromConfigMap(env, namespace)
 		default:
 			return nil, fmt.Errorf("could not add env with name %v, unknown valueFrom %v", env.Name, env.ValueFrom)
 		}
@@ -578,23 +615,77 @@ func generateEnvFromString(env config.Env) []v1.EnvVar {
 
 func (k8s *K8sImpl) generateEnvFromSecret(env config.Env, namespace string) ([]v1.EnvVar, error) {
 
-	var generatedEnv []v1.EnvVar
-
 	secret, err := k8s.clientset.CoreV1().Secrets(namespace).Get(context.TODO(), env.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("could not add env with name %v, valueFrom %v: %v", env.Name, env.ValueFrom, err)
 	}
 
+	keys := make(map[string]bool, len(secret.Data))
 	for key := range secret.Data {
+		keys[key] = true
+	}
+
+	return generateEnvFromKeys(env, keys, func(key string) *v1.EnvVarSource {
+		return &v1.EnvVarSource{
+			SecretKeyRef: &v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: env.Name},
+				Key:                  key,
+			},
+		}
+	})
+}
+
+func (k8s *K8sImpl) generateEnvFromConfigMap(env config.Env, namespace string) ([]v1.EnvVar, error) {
+
+	configMap, err := k8s.clientset.CoreV1().ConfigMaps(namespace).Get(context.TODO(), env.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not add env with name %v, valueFrom %v: %v", env.Name, env.ValueFrom, err)
+	}
+
+	keys := make(map[string]bool, len(configMap.Data))
+	for key := range configMap.Data {
+		keys[key] = true
+	}
+
+	return generateEnvFromKeys(env, keys, func(key string) *v1.EnvVarSource {
+		return &v1.EnvVarSource{
+			ConfigMapKeyRef: &v1.ConfigMapKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: env.Name},
+				Key:                  key,
+			},
+		}
+	})
+}
+
+// generateEnvFromKeys creates an env for each selected key of a Secret or ConfigMap, or for all of its keys if the
+// env doesn't select any. The env are named after the key or the name given with as, prepended by the prefix
+func generateEnvFromKeys(
+	env config.Env, keys map[string]bool, createEnvVarSource func(key string) *v1.EnvVarSource,
+) ([]v1.EnvVar, error) {
+	selectedKeys := env.Keys
+	if len(selectedKeys) == 0 {
+		for key := range keys {
+			selectedKeys = append(selectedKeys, config.EnvKey{Key: key})
+		}
+
+		sort.Slice(selectedKeys, func(i, j int) bool {
+			return selectedKeys[i].Key < selectedKeys[j].Key
+		})
+	}
+
+	var generatedEnv []v1.EnvVar
+	for _, selectedKey := range selectedKeys {
+		if !keys[selectedKey.Key] {
+			return nil, fmt.Errorf(
+				"could not add env with name %v, valueFrom %v: key %v not found", env.Name, env.ValueFrom,
+				selectedKey.Key,
+			)
+		}
+
 		generatedEnv = append(
 			generatedEnv, v1.EnvVar{
-				Name: key,
-				ValueFrom: &v1.EnvVarSource{
-					SecretKeyRef: &v1.SecretKeySelector{
-						LocalObjectReference: v1.LocalObjectReference{Name: env.Name},
-						Key:                  key,
-					},
-				},
+				Name:      env.Prefix + selectedKey.GetName(),
+				ValueFrom: createEnvVarSource(selectedKey.Key),
 			},
 		)
 	}
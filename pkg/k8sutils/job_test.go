@@ -317,6 +317,186 @@ func TestPrepareJobEnvFromString(t *testing.T) {
 	assert.Equal(t, jobEnv[0].Value, value)
 }
 
+func TestPrepareJobEnvFromSecretKeys(t *testing.T) {
+	task := config.Task{
+		Env: []config.Env{
+			{
+				Name:      "db-credentials",
+				ValueFrom: "secret",
+				Prefix:    "APP_",
+				Keys: []config.EnvKey{
+					{Key: "password", As: "DB_PASSWORD"},
+					{Key: "username"},
+				},
+			},
+		},
+	}
+
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface)
+
+	k8s := K8sImpl{
+		clientset: k8sfake.NewSimpleClientset(),
+	}
+
+	secretData := map[string][]byte{
+		"username": []byte("admin"), "password": []byte("secret"), "host": []byte("db.example.com"),
+	}
+	k8sSecret := createK8sSecretObj("db-credentials", testNamespace, secretData)
+	k8s.clientset.CoreV1().Secrets(testNamespace).Create(context.TODO(), k8sSecret, metav1.CreateOptions{})
+
+	jobEnv, err := k8s.prepareJobEnv(&task, &eventData, eventAsInterface, testNamespace)
+	require.NoError(t, err)
+
+	require.Len(t, jobEnv, 5)
+	assert.Equal(t, corev1.EnvVar{
+		Name: "APP_DB_PASSWORD",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"},
+				Key:                  "password",
+			},
+		},
+	}, jobEnv[0])
+	assert.Equal(t, corev1.EnvVar{
+		Name: "APP_username",
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "db-credentials"},
+				Key:                  "username",
+			},
+		},
+	}, jobEnv[1])
+}
+
+func TestPrepareJobEnvFromSecretKeys_KeyNotFound(t *testing.T) {
+	task := config.Task{
+		Env: []config.Env{
+			{
+				Name:      "db-credentials",
+				ValueFrom: "secret",
+				Keys:      []config.EnvKey{{Key: "token"}},
+			},
+		},
+	}
+
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface)
+
+	k8s := K8sImpl{
+		clientset: k8sfake.NewSimpleClientset(),
+	}
+
+	k8sSecret := createK8sSecretObj("db-credentials", testNamespace, map[string][]byte{"password": []byte("secret")})
+	k8s.clientset.CoreV1().Secrets(testNamespace).Create(context.TODO(), k8sSecret, metav1.CreateOptions{})
+
+	_, err := k8s.prepareJobEnv(&task, &eventData, eventAsInterface, testNamespace)
+	assert.EqualError(
+		t, err, "could not add env with name db-credentials, valueFrom secret: key token not found",
+	)
+}
+
+func TestPrepareJobEnvFromConfigMap(t *testing.T) {
+	task := config.Task{
+		Env: []config.Env{
+			{
+				Name:      "test-settings",
+				ValueFrom: "configmap",
+				Prefix:    "TEST_",
+			},
+			{
+				Name:      "db-settings",
+				ValueFrom: "configmap",
+				Keys:      []config.EnvKey{{Key: "host", As: "DB_HOST"}},
+			},
+		},
+	}
+
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface)
+
+	k8s := K8sImpl{
+		clientset: k8sfake.NewSimpleClientset(),
+	}
+
+	for name, data := range map[string]map[string]string{
+		"test-settings": {"USERS": "10", "DURATION": "5m"},
+		"db-settings":   {"host": "db.example.com", "port": "5432"},
+	} {
+		_, err := k8s.clientset.CoreV1().ConfigMaps(testNamespace).Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+			Data:       data,
+		}, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+
+	jobEnv, err := k8s.prepareJobEnv(&task, &eventData, eventAsInterface, testNamespace)
+	require.NoError(t, err)
+
+	configMapKeyRef := func(name string, key string) *corev1.EnvVarSource {
+		return &corev1.EnvVarSource{
+			ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Key:                  key,
+			},
+		}
+	}
+
+	require.Len(t, jobEnv, 6)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "TEST_DURATION", ValueFrom: configMapKeyRef("test-settings", "DURATION")},
+		{Name: "TEST_USERS", ValueFrom: configMapKeyRef("test-settings", "USERS")},
+		{Name: "DB_HOST", ValueFrom: configMapKeyRef("db-settings", "host")},
+	}, jobEnv[:3])
+}
+
+func TestPrepareJobEnvFromConfigMap_ConfigMapNotFound(t *testing.T) {
+	task := config.Task{
+		Env: []config.Env{
+			{
+				Name:      "test-settings",
+				ValueFrom: "configmap",
+			},
+		},
+	}
+
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface)
+
+	k8s := K8sImpl{
+		clientset: k8sfake.NewSimpleClientset(),
+	}
+	_, err := k8s.prepareJobEnv(&task, &eventData, eventAsInterface, testNamespace)
+	assert.EqualError(
+		t, err,
+		"could not add env with name test-settings, valueFrom configmap: configmaps \"test-settings\" not found",
+	)
+}
+
 func TestSetWorkingDir(t *testing.T) {
 	jobName := "test-job-1"
 	workingDir := "/test/dir"
@@ -1374,3 +1554,55 @@ func TestK8sImpl_CreateK8sJobPodLabels(t *testing.T) {
 		assert.Equal(t, label, jesDeploymentName)
 	}
 }
+
+func TestCreateK8sJobWithOutputs(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface)
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+
+	k8s := K8sImpl{
+		clientset: k8sClientSet,
+	}
+
+	jobSettings := JobSettings{
+		JobNamespace: testNamespace,
+		DefaultResourceRequirements: &corev1.ResourceRequirements{
+			Limits:   make(corev1.ResourceList),
+			Requests: make(corev1.ResourceList),
+		},
+		DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+		DefaultSecurityContext:    new(corev1.SecurityContext),
+	}
+
+	tasks := map[string]*config.Task{
+		"job-with-outputs":    {Name: "Build", Image: "alpine", Outputs: []string{"IMAGE_TAG"}},
+		"job-without-outputs": {Name: "Test", Image: "alpine"},
+	}
+
+	for jobName, task := range tasks {
+		err := k8s.CreateK8sJob(
+			jobName,
+			JobDetails{
+				Action: &config.Action{Name: "Build and test"},
+				Task:   task,
+			},
+			&eventData, jobSettings, eventAsInterface, testNamespace,
+		)
+		require.NoError(t, err)
+	}
+
+	job, err := k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-with-outputs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "/keptn/outputs", job.Spec.Template.Spec.Containers[0].TerminationMessagePath)
+
+	job, err = k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-without-outputs", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, job.Spec.Template.Spec.Containers[0].TerminationMessagePath)
+}
@@ -69,6 +69,43 @@ func (k8s *K8sImpl) GetLogsOfPod(jobName string, namespace string) (string, erro
 	return logs.String(), nil
 }
 
+// JobContainerTermination contains the exit code and the termination message of the job container of a job
+type JobContainerTermination struct {
+	ExitCode int32
+	Message  string
+}
+
+// GetJobContainerTermination returns the exit code and the termination message of the job container of a job in a
+// namespace. If the job has multiple pods, the termination of the last pod with a terminated job container is returned
+func (k8s *K8sImpl) GetJobContainerTermination(jobName string, namespace string) (*JobContainerTermination, error) {
+	list, err := k8s.clientset.CoreV1().Pods(namespace).List(
+		context.TODO(), metav1.ListOptions{
+			LabelSelector: "job-name=" + jobName,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var termination *JobContainerTermination
+	for _, pod := range list.Items {
+		for _, container := range getTerminatedContainersWithStatusOfPod(pod) {
+			if container.containerType == jobContainerType {
+				termination = &JobContainerTermination{
+					ExitCode: container.status.ExitCode,
+					Message:  container.status.Message,
+				}
+			}
+		}
+	}
+
+	if termination == nil {
+		return nil, fmt.Errorf("no terminated job container found for job %s", jobName)
+	}
+
+	return termination, nil
+}
+
 const (
 	// Indicates that the container is an Init container
 	initContainerType = iota
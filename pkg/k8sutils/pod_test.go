@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -162,3 +163,51 @@ func TestGetLogsOfPodHappyPath(t *testing.T) {
 	assert.Contains(t, k8sClientSet.Actions(), getLogActionInitContainer)
 	assert.Contains(t, k8sClientSet.Actions(), getLogActionContainer)
 }
+
+func TestGetJobContainerTermination(t *testing.T) {
+	k8sClientSet := k8sfake.NewSimpleClientset()
+
+	jobName := "completed-job"
+	namespace := "namespace"
+
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "some-job-pod",
+			Labels: map[string]string{"job-name": jobName},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init-" + jobName}},
+			Containers:     []v1.Container{{Name: jobName}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodSucceeded,
+			InitContainerStatuses: []v1.ContainerStatus{
+				{
+					Name: "init-" + jobName,
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{ExitCode: 0, Message: "Init done."},
+					},
+				},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name: jobName,
+					State: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{ExitCode: 2, Message: "IMAGE_TAG=1.2.3\n"},
+					},
+				},
+			},
+		},
+	}
+	_, err := k8sClientSet.CoreV1().Pods(namespace).Create(context.Background(), &pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	termination, err := k8s.GetJobContainerTermination(jobName, namespace)
+	require.NoError(t, err)
+	assert.Equal(t, &JobContainerTermination{ExitCode: 2, Message: "IMAGE_TAG=1.2.3\n"}, termination)
+
+	_, err = k8s.GetJobContainerTermination("unknown-job", namespace)
+	assert.ErrorContains(t, err, "no terminated job container found for job unknown-job")
+}
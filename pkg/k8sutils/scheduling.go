@@ -0,0 +1,233 @@
+package k8sutils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gobwas/glob"
+	v1 "k8s.io/api/core/v1"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+// ErrSchedulingNotAllowed indicates that a task uses a nodeSelector, toleration, node affinity, priority class or
+// runtime class which isn't allowed by the scheduling allowlist of the job-executor-service
+var /*const*/ ErrSchedulingNotAllowed = errors.New("scheduling value is not allowed")
+
+// JobSchedulingSettings contains the default scheduling fields of the pods of the jobs and the values tasks are
+// allowed to use instead of the defaults
+type JobSchedulingSettings struct {
+	NodeSelector      map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations       []v1.Toleration     `json:"tolerations,omitempty"`
+	Affinity          *v1.Affinity        `json:"affinity,omitempty"`
+	PriorityClassName string              `json:"priorityClassName,omitempty"`
+	RuntimeClassName  string              `json:"runtimeClassName,omitempty"`
+	Allowed           SchedulingAllowList `json:"allowed"`
+}
+
+// SchedulingAllowList contains glob patterns of the scheduling values tasks are allowed to use. NodeSelectors and
+// Tolerations are matched against key=value. Match expressions of the node affinity with operator In select nodes like a
+// nodeSelector, so every value is matched against NodeSelectors as key=value. All other operators don't select specific
+// values, their keys have to match NodeAffinityKeys. An empty list doesn't allow tasks to set the respective field
+type SchedulingAllowList struct {
+	NodeSelectors      []string `json:"nodeSelectors,omitempty"`
+	Tolerations        []string `json:"tolerations,omitempty"`
+	NodeAffinityKeys   []string `json:"nodeAffinityKeys,omitempty"`
+	PriorityClassNames []string `json:"priorityClassNames,omitempty"`
+	RuntimeClassNames  []string `json:"runtimeClassNames,omitempty"`
+}
+
+// ReadJobSchedulingSettings reads the JSON file defined in jobSchedulingFilePath and parses it into the
+// JobSchedulingSettings, all patterns of the allowlist are checked for validity
+func ReadJobSchedulingSettings(jobSchedulingFilePath string) (*JobSchedulingSettings, error) {
+	jobSchedulingBytes, err := os.ReadFile(jobSchedulingFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job scheduling config file %s: %w", jobSchedulingFilePath, err)
+	}
+
+	var settings JobSchedulingSettings
+	err = json.Unmarshal(jobSchedulingBytes, &settings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job scheduling configuration format: %w", err)
+	}
+
+	allowed := settings.Allowed
+	for _, patterns := range [][]string{
+		allowed.NodeSelectors, allowed.Tolerations, allowed.NodeAffinityKeys, allowed.PriorityClassNames,
+		allowed.RuntimeClassNames,
+	} {
+		for _, pattern := range patterns {
+			if _, err := glob.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("invalid pattern %s in job scheduling allowlist: %w", pattern, err)
+			}
+		}
+	}
+
+	return &settings, nil
+}
+
+// applyScheduling sets the scheduling fields of the pod of the job. Each field that is set in the task replaces the
+// default of the job-executor-service, but only if the allowlist permits the values of the task
+func applyScheduling(podSpec *v1.PodSpec, task *config.Task, settings *JobSchedulingSettings) error {
+	if settings == nil {
+		settings = &JobSchedulingSettings{}
+	}
+
+	allowed := settings.Allowed
+
+	podSpec.NodeSelector = settings.NodeSelector
+	if task.NodeSelector != nil {
+		for key, value := range task.NodeSelector {
+			if !matchesAny(allowed.NodeSelectors, key+"="+value) {
+				return fmt.Errorf("nodeSelector %s=%s: %w", key, value, ErrSchedulingNotAllowed)
+			}
+		}
+
+		podSpec.NodeSelector = task.NodeSelector
+	}
+
+	podSpec.Tolerations = settings.Tolerations
+	if task.Tolerations != nil {
+		var tolerations []v1.Toleration
+		for _, toleration := range task.Tolerations {
+			if !matchesAny(allowed.Tolerations, toleration.Key+"="+toleration.Value) {
+				return fmt.Errorf(
+					"toleration %s=%s: %w", toleration.Key, toleration.Value, ErrSchedulingNotAllowed,
+				)
+			}
+
+			tolerations = append(tolerations, v1.Toleration{
+				Key:               toleration.Key,
+				Operator:          v1.TolerationOperator(toleration.Operator),
+				Value:             toleration.Value,
+				Effect:            v1.TaintEffect(toleration.Effect),
+				TolerationSeconds: toleration.TolerationSeconds,
+			})
+		}
+
+		podSpec.Tolerations = tolerations
+	}
+
+	podSpec.Affinity = settings.Affinity
+	if task.Affinity != nil {
+		affinity, err := createAffinity(task.Affinity, allowed)
+		if err != nil {
+			return err
+		}
+
+		podSpec.Affinity = affinity
+	}
+
+	podSpec.PriorityClassName = settings.PriorityClassName
+	if task.PriorityClassName != "" {
+		if !matchesAny(allowed.PriorityClassNames, task.PriorityClassName) {
+			return fmt.Errorf("priorityClassName %s: %w", task.PriorityClassName, ErrSchedulingNotAllowed)
+		}
+
+		podSpec.PriorityClassName = task.PriorityClassName
+	}
+
+	runtimeClassName := settings.RuntimeClassName
+	if task.RuntimeClassName != "" {
+		if !matchesAny(allowed.RuntimeClassNames, task.RuntimeClassName) {
+			return fmt.Errorf("runtimeClassName %s: %w", task.RuntimeClassName, ErrSchedulingNotAllowed)
+		}
+
+		runtimeClassName = task.RuntimeClassName
+	}
+
+	if runtimeClassName != "" {
+		podSpec.RuntimeClassName = &runtimeClassName
+	}
+
+	return nil
+}
+
+// createAffinity converts the node affinity of the task into the Kubernetes Affinity, all match expressions must be
+// allowed by the allowlist
+func createAffinity(affinity *config.Affinity, allowed SchedulingAllowList) (*v1.Affinity, error) {
+	if affinity.NodeAffinity == nil {
+		return &v1.Affinity{}, nil
+	}
+
+	createTerm := func(term config.NodeSelectorTerm) (v1.NodeSelectorTerm, error) {
+		var requirements []v1.NodeSelectorRequirement
+		for _, expression := range term.MatchExpressions {
+			if err := isMatchExpressionAllowed(expression, allowed); err != nil {
+				return v1.NodeSelectorTerm{}, err
+			}
+
+			requirements = append(requirements, v1.NodeSelectorRequirement{
+				Key:      expression.Key,
+				Operator: v1.NodeSelectorOperator(expression.Operator),
+				Values:   expression.Values,
+			})
+		}
+
+		return v1.NodeSelectorTerm{MatchExpressions: requirements}, nil
+	}
+
+	nodeAffinity := &v1.NodeAffinity{}
+
+	if required := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+		nodeSelector := &v1.NodeSelector{}
+		for _, term := range required.NodeSelectorTerms {
+			nodeSelectorTerm, err := createTerm(term)
+			if err != nil {
+				return nil, err
+			}
+
+			nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, nodeSelectorTerm)
+		}
+
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = nodeSelector
+	}
+
+	for _, term := range affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		preference, err := createTerm(term.Preference)
+		if err != nil {
+			return nil, err
+		}
+
+		nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			nodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+			v1.PreferredSchedulingTerm{Weight: term.Weight, Preference: preference},
+		)
+	}
+
+	return &v1.Affinity{NodeAffinity: nodeAffinity}, nil
+}
+
+// isMatchExpressionAllowed checks that each value of an expression with operator In is allowed as key=value by the
+// nodeSelectors of the allowlist and that the keys of all other expressions are allowed by the nodeAffinityKeys
+func isMatchExpressionAllowed(expression config.NodeSelectorRequirement, allowed SchedulingAllowList) error {
+	if expression.Operator != string(v1.NodeSelectorOpIn) {
+		if !matchesAny(allowed.NodeAffinityKeys, expression.Key) {
+			return fmt.Errorf("node affinity key %s: %w", expression.Key, ErrSchedulingNotAllowed)
+		}
+
+		return nil
+	}
+
+	for _, value := range expression.Values {
+		if !matchesAny(allowed.NodeSelectors, expression.Key+"="+value) {
+			return fmt.Errorf("node affinity %s=%s: %w", expression.Key, value, ErrSchedulingNotAllowed)
+		}
+	}
+
+	return nil
+}
+
+// matchesAny returns true if the value matches one of the glob patterns
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		compiledGlob, err := glob.Compile(pattern)
+		if err == nil && compiledGlob.Match(value) {
+			return true
+		}
+	}
+
+	return false
+}
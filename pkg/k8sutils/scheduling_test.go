@@ -0,0 +1,330 @@
+package k8sutils
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+const testJobScheduling = `{
+  "nodeSelector": {"node-pool": "jobs"},
+  "tolerations": [{"key": "dedicated", "operator": "Equal", "value": "jobs", "effect": "NoSchedule"}],
+  "affinity": {
+    "nodeAffinity": {
+      "requiredDuringSchedulingIgnoredDuringExecution": {
+        "nodeSelectorTerms": [
+          {"matchExpressions": [{"key": "node.kubernetes.io/lifecycle", "operator": "NotIn", "values": ["spot"]}]}
+        ]
+      }
+    }
+  },
+  "priorityClassName": "low-priority",
+  "allowed": {
+    "nodeSelectors": ["node-pool=*", "disktype=ssd"],
+    "tolerations": ["dedicated=performance"],
+    "nodeAffinityKeys": ["node.kubernetes.io/lifecycle"],
+    "priorityClassNames": ["high-priority"],
+    "runtimeClassNames": ["gvisor"]
+  }
+}`
+
+func readTestJobScheduling(t *testing.T, content string) (*JobSchedulingSettings, error) {
+	filePath := filepath.Join(t.TempDir(), "job-scheduling.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0600))
+
+	return ReadJobSchedulingSettings(filePath)
+}
+
+func TestReadJobSchedulingSettings(t *testing.T) {
+	settings, err := readTestJobScheduling(t, testJobScheduling)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"node-pool": "jobs"}, settings.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "jobs", Effect: corev1.TaintEffectNoSchedule},
+	}, settings.Tolerations)
+	require.NotNil(t, settings.Affinity)
+	assert.Equal(t, "low-priority", settings.PriorityClassName)
+	assert.Empty(t, settings.RuntimeClassName)
+	assert.Equal(t, []string{"gvisor"}, settings.Allowed.RuntimeClassNames)
+}
+
+func TestReadJobSchedulingSettingsInvalid(t *testing.T) {
+	_, err := ReadJobSchedulingSettings(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "error reading job scheduling config file")
+
+	_, err = readTestJobScheduling(t, `{"nodeSelector": []}`)
+	assert.ErrorContains(t, err, "invalid job scheduling configuration format")
+
+	_, err = readTestJobScheduling(t, `{"allowed": {"priorityClassNames": ["high-["]}}`)
+	assert.ErrorContains(t, err, "invalid pattern high-[ in job scheduling allowlist")
+}
+
+func createTestJobWithScheduling(
+	t *testing.T, task *config.Task, settings *JobSchedulingSettings,
+) (*corev1.PodSpec, error) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	err := k8s.CreateK8sJob(
+		"job-with-scheduling",
+		JobDetails{
+			Action: &config.Action{Name: "Run tests"},
+			Task:   task,
+		},
+		&eventData, JobSettings{
+			JobNamespace: testNamespace,
+			DefaultResourceRequirements: &corev1.ResourceRequirements{
+				Limits:   make(corev1.ResourceList),
+				Requests: make(corev1.ResourceList),
+			},
+			DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+			DefaultSecurityContext:    new(corev1.SecurityContext),
+			JobScheduling:             settings,
+		}, eventAsInterface, testNamespace,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-with-scheduling", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	return &job.Spec.Template.Spec, nil
+}
+
+func TestCreateK8sJobWithDefaultScheduling(t *testing.T) {
+	settings, err := readTestJobScheduling(t, testJobScheduling)
+	require.NoError(t, err)
+
+	podSpec, err := createTestJobWithScheduling(t, &config.Task{Name: "Build", Image: "golang"}, settings)
+	require.NoError(t, err)
+
+	assert.Equal(t, settings.NodeSelector, podSpec.NodeSelector)
+	assert.Equal(t, settings.Tolerations, podSpec.Tolerations)
+	assert.Equal(t, settings.Affinity, podSpec.Affinity)
+	assert.Equal(t, "low-priority", podSpec.PriorityClassName)
+	assert.Nil(t, podSpec.RuntimeClassName)
+
+	podSpec, err = createTestJobWithScheduling(t, &config.Task{Name: "Build", Image: "golang"}, nil)
+	require.NoError(t, err)
+
+	assert.Nil(t, podSpec.NodeSelector)
+	assert.Nil(t, podSpec.Tolerations)
+	assert.Nil(t, podSpec.Affinity)
+	assert.Empty(t, podSpec.PriorityClassName)
+	assert.Nil(t, podSpec.RuntimeClassName)
+}
+
+func TestCreateK8sJobWithTaskScheduling(t *testing.T) {
+	settings, err := readTestJobScheduling(t, testJobScheduling)
+	require.NoError(t, err)
+
+	task := &config.Task{
+		Name:         "Performance tests",
+		Image:        "locustio/locust",
+		NodeSelector: map[string]string{"node-pool": "performance"},
+		Tolerations: []config.Toleration{
+			{Key: "dedicated", Operator: "Equal", Value: "performance", Effect: "NoSchedule"},
+		},
+		Affinity: &config.Affinity{
+			NodeAffinity: &config.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []config.PreferredSchedulingTerm{
+					{
+						Weight: 10,
+						Preference: config.NodeSelectorTerm{
+							MatchExpressions: []config.NodeSelectorRequirement{
+								{Key: "disktype", Operator: "In", Values: []string{"ssd"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		PriorityClassName: "high-priority",
+		RuntimeClassName:  "gvisor",
+	}
+
+	podSpec, err := createTestJobWithScheduling(t, task, settings)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"node-pool": "performance"}, podSpec.NodeSelector)
+	assert.Equal(t, []corev1.Toleration{
+		{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "performance", Effect: corev1.TaintEffectNoSchedule},
+	}, podSpec.Tolerations)
+	assert.Equal(t, &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+				{
+					Weight: 10,
+					Preference: corev1.NodeSelectorTerm{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}},
+						},
+					},
+				},
+			},
+		},
+	}, podSpec.Affinity)
+	assert.Equal(t, "high-priority", podSpec.PriorityClassName)
+	require.NotNil(t, podSpec.RuntimeClassName)
+	assert.Equal(t, "gvisor", *podSpec.RuntimeClassName)
+}
+
+func TestCreateK8sJobWithTaskSchedulingNotAllowed(t *testing.T) {
+	settings, err := readTestJobScheduling(t, testJobScheduling)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		task          config.Task
+		expectedError string
+	}{
+		{
+			name:          "nodeSelector",
+			task:          config.Task{NodeSelector: map[string]string{"kubernetes.io/hostname": "node-1"}},
+			expectedError: "nodeSelector kubernetes.io/hostname=node-1: scheduling value is not allowed",
+		},
+		{
+			name: "toleration",
+			task: config.Task{
+				Tolerations: []config.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: "Exists"}},
+			},
+			expectedError: "toleration node-role.kubernetes.io/control-plane=: scheduling value is not allowed",
+		},
+		{
+			name: "node affinity",
+			task: config.Task{
+				Affinity: &config.Affinity{
+					NodeAffinity: &config.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &config.NodeSelector{
+							NodeSelectorTerms: []config.NodeSelectorTerm{
+								{
+									MatchExpressions: []config.NodeSelectorRequirement{
+										{Key: "kubernetes.io/hostname", Operator: "In", Values: []string{"node-1"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedError: "node affinity kubernetes.io/hostname=node-1: scheduling value is not allowed",
+		},
+		{
+			name: "node affinity key",
+			task: config.Task{
+				Affinity: &config.Affinity{
+					NodeAffinity: &config.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &config.NodeSelector{
+							NodeSelectorTerms: []config.NodeSelectorTerm{
+								{
+									MatchExpressions: []config.NodeSelectorRequirement{
+										{Key: "disktype", Operator: "Exists"},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedError: "node affinity key disktype: scheduling value is not allowed",
+		},
+		{
+			name:          "priorityClassName",
+			task:          config.Task{PriorityClassName: "system-cluster-critical"},
+			expectedError: "priorityClassName system-cluster-critical: scheduling value is not allowed",
+		},
+		{
+			name:          "runtimeClassName",
+			task:          config.Task{RuntimeClassName: "kata"},
+			expectedError: "runtimeClassName kata: scheduling value is not allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			task := test.task
+			task.Name = "Performance tests"
+			task.Image = "locustio/locust"
+
+			_, err := createTestJobWithScheduling(t, &task, settings)
+			assert.ErrorContains(t, err, "unable to schedule job for task Performance tests: "+test.expectedError)
+		})
+	}
+}
+
+func TestCreateAffinityAllowList(t *testing.T) {
+	// Tasks can only select the node pool jobs, but the key node-pool may be used with operators other than In
+	allowed := SchedulingAllowList{
+		NodeSelectors:    []string{"node-pool=jobs"},
+		NodeAffinityKeys: []string{"node-pool"},
+	}
+
+	tests := []struct {
+		name          string
+		expression    config.NodeSelectorRequirement
+		expectedError string
+	}{
+		{
+			name:       "allowed value",
+			expression: config.NodeSelectorRequirement{Key: "node-pool", Operator: "In", Values: []string{"jobs"}},
+		},
+		{
+			name:          "value barred from nodeSelector",
+			expression:    config.NodeSelectorRequirement{Key: "node-pool", Operator: "In", Values: []string{"jobs", "perf"}},
+			expectedError: "node affinity node-pool=perf: scheduling value is not allowed",
+		},
+		{
+			name:       "allowed key",
+			expression: config.NodeSelectorRequirement{Key: "node-pool", Operator: "NotIn", Values: []string{"perf"}},
+		},
+		{
+			name:          "key without allowlist entry",
+			expression:    config.NodeSelectorRequirement{Key: "disktype", Operator: "Exists"},
+			expectedError: "node affinity key disktype: scheduling value is not allowed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			affinity := &config.Affinity{
+				NodeAffinity: &config.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &config.NodeSelector{
+						NodeSelectorTerms: []config.NodeSelectorTerm{
+							{MatchExpressions: []config.NodeSelectorRequirement{test.expression}},
+						},
+					},
+				},
+			}
+
+			_, err := createAffinity(affinity, allowed)
+			if test.expectedError != "" {
+				assert.ErrorIs(t, err, ErrSchedulingNotAllowed)
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
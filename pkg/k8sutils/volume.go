@@ -0,0 +1,188 @@
+package k8sutils
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+	"keptn-contrib/job-executor-service/pkg/utils"
+)
+
+// DefaultJobVolumeSize is the size limit of the job volume if neither the job-executor-service nor the task configure
+// a size
+const DefaultJobVolumeSize = "20Mi"
+
+// DefaultJobVolumeMountPath is the path the job volume is mounted at if neither the job-executor-service nor the task
+// configure a mount path
+const DefaultJobVolumeMountPath = "/keptn"
+
+// jobVolumeName is the name of the volume the files of the task are mounted into
+const jobVolumeName = "job-volume"
+
+// taskVolumeNamePrefix is the prefix of the names of the ConfigMap and Secret volumes of a task in the pod of the job
+const taskVolumeNamePrefix = "task-volume-"
+
+// ErrVolumeSourceNotAllowed indicates that a task wants to mount a ConfigMap or Secret which isn't allowed by the
+// volume source allowlist of the job-executor-service
+var /*const*/ ErrVolumeSourceNotAllowed = errors.New("volume source is not allowed")
+
+// JobVolumeSettings contains the defaults for the volume the files of a task are mounted into and the maximum size a
+// task is allowed to request for it
+type JobVolumeSettings struct {
+	DefaultSize      resource.Quantity
+	DefaultMountPath string
+	MaxSize          *resource.Quantity
+}
+
+// CreateJobVolumeSettings parses the default and maximum size of the job volume. An empty maximum size means that
+// tasks can request volumes of any size
+func CreateJobVolumeSettings(defaultSize, defaultMountPath, maxSize string) (*JobVolumeSettings, error) {
+	settings := &JobVolumeSettings{
+		DefaultMountPath: defaultMountPath,
+	}
+
+	if !path.IsAbs(defaultMountPath) {
+		return nil, fmt.Errorf("default job volume mount path '%v' must be an absolute path", defaultMountPath)
+	}
+
+	var err error
+	settings.DefaultSize, err = resource.ParseQuantity(defaultSize)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse default job volume size '%v': %v", defaultSize, err.Error())
+	}
+
+	if maxSize != "" {
+		maxQuantity, err := resource.ParseQuantity(maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse max job volume size '%v': %v", maxSize, err.Error())
+		}
+
+		if settings.DefaultSize.Cmp(maxQuantity) > 0 {
+			return nil, fmt.Errorf(
+				"default job volume size %v exceeds the max job volume size %v", defaultSize, maxSize,
+			)
+		}
+
+		settings.MaxSize = &maxQuantity
+	}
+
+	return settings, nil
+}
+
+// createJobVolume returns the emptyDir volume source and the mount path of the job volume of the task. The volume
+// settings of the task override the defaults of the job-executor-service, but the size must not exceed the maximum
+// job volume size
+func createJobVolume(task *config.Task, settings *JobVolumeSettings) (v1.EmptyDirVolumeSource, string, error) {
+	if settings == nil {
+		settings = &JobVolumeSettings{
+			DefaultSize:      resource.MustParse(DefaultJobVolumeSize),
+			DefaultMountPath: DefaultJobVolumeMountPath,
+		}
+	}
+
+	size := settings.DefaultSize
+	mountPath := settings.DefaultMountPath
+	medium := v1.StorageMediumDefault
+
+	if task.Volume != nil {
+		if task.Volume.Size != "" {
+			var err error
+			size, err = resource.ParseQuantity(task.Volume.Size)
+			if err != nil {
+				return v1.EmptyDirVolumeSource{}, "", fmt.Errorf(
+					"unable to parse volume size '%v': %v", task.Volume.Size, err.Error(),
+				)
+			}
+		}
+
+		if task.Volume.MountPath != "" {
+			mountPath = task.Volume.MountPath
+		}
+
+		if task.Volume.Medium == config.VolumeMediumMemory {
+			medium = v1.StorageMediumMemory
+		}
+	}
+
+	if settings.MaxSize != nil && size.Cmp(*settings.MaxSize) > 0 {
+		return v1.EmptyDirVolumeSource{}, "", fmt.Errorf(
+			"volume size %v exceeds the max job volume size %v", size.String(), settings.MaxSize.String(),
+		)
+	}
+
+	return v1.EmptyDirVolumeSource{
+		Medium:    medium,
+		SizeLimit: &size,
+	}, mountPath, nil
+}
+
+// createTaskVolumes returns the ConfigMap and Secret volumes of the task and the read-only mounts for the job
+// container. Every ConfigMap and Secret has to be allowed by the volume source allowlist of the job-executor-service
+func createTaskVolumes(
+	task *config.Task, jobVolumeMountPath string, allowList *utils.VolumeSourceAllowList,
+) ([]v1.Volume, []v1.VolumeMount, error) {
+	var volumes []v1.Volume
+	var volumeMounts []v1.VolumeMount
+
+	for index, volume := range task.Volumes {
+		if path.Clean(volume.MountPath) == path.Clean(jobVolumeMountPath) {
+			return nil, nil, fmt.Errorf(
+				"volume at %v can't be mounted at the mount path of the job volume", volume.MountPath,
+			)
+		}
+
+		volumeName := fmt.Sprintf("%s%d", taskVolumeNamePrefix, index)
+
+		var volumeSource v1.VolumeSource
+		if volume.ConfigMap != nil {
+			if !allowList.IsConfigMapAllowed(volume.ConfigMap.Name) {
+				return nil, nil, fmt.Errorf("configMap %v: %w", volume.ConfigMap.Name, ErrVolumeSourceNotAllowed)
+			}
+
+			volumeSource.ConfigMap = &v1.ConfigMapVolumeSource{
+				LocalObjectReference: v1.LocalObjectReference{Name: volume.ConfigMap.Name},
+				Items:                createKeyToPaths(volume.ConfigMap.Items),
+				DefaultMode:          volume.ConfigMap.DefaultMode,
+			}
+		} else if volume.Secret != nil {
+			if !allowList.IsSecretAllowed(volume.Secret.Name) {
+				return nil, nil, fmt.Errorf("secret %v: %w", volume.Secret.Name, ErrVolumeSourceNotAllowed)
+			}
+
+			volumeSource.Secret = &v1.SecretVolumeSource{
+				SecretName:  volume.Secret.Name,
+				Items:       createKeyToPaths(volume.Secret.Items),
+				DefaultMode: volume.Secret.DefaultMode,
+			}
+		}
+
+		volumes = append(volumes, v1.Volume{
+			Name:         volumeName,
+			VolumeSource: volumeSource,
+		})
+		volumeMounts = append(volumeMounts, v1.VolumeMount{
+			Name:      volumeName,
+			MountPath: volume.MountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return volumes, volumeMounts, nil
+}
+
+func createKeyToPaths(items []config.VolumeItem) []v1.KeyToPath {
+	var keyToPaths []v1.KeyToPath
+	for _, item := range items {
+		keyToPaths = append(keyToPaths, v1.KeyToPath{
+			Key:  item.Key,
+			Path: item.GetPath(),
+			Mode: item.Mode,
+		})
+	}
+
+	return keyToPaths
+}
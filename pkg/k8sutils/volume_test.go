@@ -0,0 +1,330 @@
+package k8sutils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+	"keptn-contrib/job-executor-service/pkg/utils"
+)
+
+func TestCreateJobVolumeSettings(t *testing.T) {
+	settings, err := CreateJobVolumeSettings("50Mi", "/data", "1Gi")
+	require.NoError(t, err)
+	assert.Equal(t, "50Mi", settings.DefaultSize.String())
+	assert.Equal(t, "/data", settings.DefaultMountPath)
+	require.NotNil(t, settings.MaxSize)
+	assert.Equal(t, "1Gi", settings.MaxSize.String())
+
+	settings, err = CreateJobVolumeSettings("50Mi", "/data", "")
+	require.NoError(t, err)
+	assert.Nil(t, settings.MaxSize)
+}
+
+func TestCreateJobVolumeSettingsInvalid(t *testing.T) {
+	_, err := CreateJobVolumeSettings("fifty", "/data", "")
+	assert.ErrorContains(t, err, "unable to parse default job volume size 'fifty'")
+
+	_, err = CreateJobVolumeSettings("50Mi", "data", "")
+	assert.ErrorContains(t, err, "default job volume mount path 'data' must be an absolute path")
+
+	_, err = CreateJobVolumeSettings("50Mi", "/data", "one")
+	assert.ErrorContains(t, err, "unable to parse max job volume size 'one'")
+
+	_, err = CreateJobVolumeSettings("2Gi", "/data", "1Gi")
+	assert.ErrorContains(t, err, "default job volume size 2Gi exceeds the max job volume size 1Gi")
+}
+
+func TestCreateK8sJobVolume(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	volumeSettings, err := CreateJobVolumeSettings("50Mi", "/data", "1Gi")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		volume            *config.JobVolume
+		volumeSettings    *JobVolumeSettings
+		expectedSize      string
+		expectedMountPath string
+		expectedMedium    corev1.StorageMedium
+	}{
+		{
+			name:              "built-in defaults",
+			expectedSize:      "20Mi",
+			expectedMountPath: "/keptn",
+			expectedMedium:    corev1.StorageMediumDefault,
+		},
+		{
+			name:              "defaults of the job-executor-service",
+			volumeSettings:    volumeSettings,
+			expectedSize:      "50Mi",
+			expectedMountPath: "/data",
+			expectedMedium:    corev1.StorageMediumDefault,
+		},
+		{
+			name:              "volume of the task",
+			volume:            &config.JobVolume{Size: "1Gi", MountPath: "/reports", Medium: "Memory"},
+			volumeSettings:    volumeSettings,
+			expectedSize:      "1Gi",
+			expectedMountPath: "/reports",
+			expectedMedium:    corev1.StorageMediumMemory,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClientSet := k8sfake.NewSimpleClientset()
+			k8s := K8sImpl{clientset: k8sClientSet}
+
+			err := k8s.CreateK8sJob(
+				"job-with-volume",
+				JobDetails{
+					Action: &config.Action{Name: "Run tests"},
+					Task:   &config.Task{Name: "Generate report", Image: "alpine", Volume: test.volume},
+				},
+				&eventData, JobSettings{
+					JobNamespace: testNamespace,
+					DefaultResourceRequirements: &corev1.ResourceRequirements{
+						Limits:   make(corev1.ResourceList),
+						Requests: make(corev1.ResourceList),
+					},
+					DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+					DefaultSecurityContext:    new(corev1.SecurityContext),
+					JobVolumeSettings:         test.volumeSettings,
+				}, eventAsInterface, testNamespace,
+			)
+			require.NoError(t, err)
+
+			job, err := k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-with-volume", metav1.GetOptions{})
+			require.NoError(t, err)
+
+			podSpec := job.Spec.Template.Spec
+			expectedSize := resource.MustParse(test.expectedSize)
+			assert.Equal(t, []corev1.Volume{
+				{
+					Name: "job-volume",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{Medium: test.expectedMedium, SizeLimit: &expectedSize},
+					},
+				},
+			}, podSpec.Volumes)
+
+			expectedVolumeMount := corev1.VolumeMount{Name: "job-volume", MountPath: test.expectedMountPath}
+			assert.Equal(t, []corev1.VolumeMount{expectedVolumeMount}, podSpec.InitContainers[0].VolumeMounts)
+			assert.Equal(t, []corev1.VolumeMount{expectedVolumeMount}, podSpec.Containers[0].VolumeMounts)
+			assert.Contains(t, podSpec.InitContainers[0].Env, corev1.EnvVar{
+				Name: "JOB_VOLUME_MOUNT_PATH", Value: test.expectedMountPath,
+			})
+		})
+	}
+}
+
+func TestCreateK8sJobVolumeExceedsMaxSize(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	volumeSettings, err := CreateJobVolumeSettings("50Mi", "/keptn", "1Gi")
+	require.NoError(t, err)
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	err = k8s.CreateK8sJob(
+		"job-with-volume",
+		JobDetails{
+			Action: &config.Action{Name: "Run tests"},
+			Task: &config.Task{
+				Name: "Generate report", Image: "alpine", Volume: &config.JobVolume{Size: "2Gi"},
+			},
+		},
+		&eventData, JobSettings{
+			JobNamespace:      testNamespace,
+			JobVolumeSettings: volumeSettings,
+		}, eventAsInterface, testNamespace,
+	)
+	assert.ErrorContains(t, err, "unable to create job volume for task Generate report: volume size 2Gi exceeds the max job volume size 1Gi")
+
+	jobs, err := k8sClientSet.BatchV1().Jobs(testNamespace).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestCreateK8sJobWithTaskVolumes(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	allowList, err := utils.BuildVolumeSourceAllowList("configmap/tool-config,secret/kubeconfig")
+	require.NoError(t, err)
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	defaultMode := int32(0400)
+	mode := int32(0444)
+	err = k8s.CreateK8sJob(
+		"job-with-volumes",
+		JobDetails{
+			Action: &config.Action{Name: "Deploy"},
+			Task: &config.Task{
+				Name:  "Apply manifests",
+				Image: "bitnami/kubectl",
+				Volumes: []config.Volume{
+					{
+						MountPath: "/kube",
+						Secret: &config.VolumeSource{
+							Name:        "kubeconfig",
+							DefaultMode: &defaultMode,
+							Items: []config.VolumeItem{
+								{Key: "config"},
+								{Key: "ca.crt", Path: "certs/ca.crt", Mode: &mode},
+							},
+						},
+					},
+					{
+						MountPath: "/etc/tool",
+						ConfigMap: &config.VolumeSource{Name: "tool-config"},
+					},
+				},
+			},
+		},
+		&eventData, JobSettings{
+			JobNamespace: testNamespace,
+			DefaultResourceRequirements: &corev1.ResourceRequirements{
+				Limits:   make(corev1.ResourceList),
+				Requests: make(corev1.ResourceList),
+			},
+			DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+			DefaultSecurityContext:    new(corev1.SecurityContext),
+			AllowedVolumeSources:      allowList,
+		}, eventAsInterface, testNamespace,
+	)
+	require.NoError(t, err)
+
+	job, err := k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-with-volumes", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "task-volume-0",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  "kubeconfig",
+				DefaultMode: &defaultMode,
+				Items: []corev1.KeyToPath{
+					{Key: "config", Path: "config"},
+					{Key: "ca.crt", Path: "certs/ca.crt", Mode: &mode},
+				},
+			},
+		},
+	})
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "task-volume-1",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "tool-config"},
+			},
+		},
+	})
+
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "task-volume-0", MountPath: "/kube", ReadOnly: true,
+	})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name: "task-volume-1", MountPath: "/etc/tool", ReadOnly: true,
+	})
+	assert.Len(t, podSpec.InitContainers[0].VolumeMounts, 1)
+}
+
+func TestCreateK8sJobWithTaskVolumesNotAllowed(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	allowList, err := utils.BuildVolumeSourceAllowList("configmap/*")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		volume        config.Volume
+		allowList     *utils.VolumeSourceAllowList
+		expectedError string
+	}{
+		{
+			name:          "secret not in allowlist",
+			volume:        config.Volume{MountPath: "/kube", Secret: &config.VolumeSource{Name: "kubeconfig"}},
+			allowList:     allowList,
+			expectedError: "secret kubeconfig: volume source is not allowed",
+		},
+		{
+			name:          "no allowlist",
+			volume:        config.Volume{MountPath: "/etc/tool", ConfigMap: &config.VolumeSource{Name: "tool-config"}},
+			expectedError: "configMap tool-config: volume source is not allowed",
+		},
+		{
+			name:          "mount path of the job volume",
+			volume:        config.Volume{MountPath: "/keptn", ConfigMap: &config.VolumeSource{Name: "tool-config"}},
+			allowList:     allowList,
+			expectedError: "volume at /keptn can't be mounted at the mount path of the job volume",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k8sClientSet := k8sfake.NewSimpleClientset()
+			k8s := K8sImpl{clientset: k8sClientSet}
+
+			err := k8s.CreateK8sJob(
+				"job-with-volumes",
+				JobDetails{
+					Action: &config.Action{Name: "Deploy"},
+					Task: &config.Task{
+						Name: "Apply manifests", Image: "bitnami/kubectl", Volumes: []config.Volume{test.volume},
+					},
+				},
+				&eventData, JobSettings{
+					JobNamespace:         testNamespace,
+					AllowedVolumeSources: test.allowList,
+				}, eventAsInterface, testNamespace,
+			)
+			assert.ErrorContains(t, err, "unable to create volumes for task Apply manifests: "+test.expectedError)
+
+			jobs, err := k8sClientSet.BatchV1().Jobs(testNamespace).List(context.TODO(), metav1.ListOptions{})
+			require.NoError(t, err)
+			assert.Empty(t, jobs.Items)
+		})
+	}
+}
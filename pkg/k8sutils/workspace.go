@@ -0,0 +1,96 @@
+package k8sutils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+// workspaceVolumeName is the name of the volume of the workspace in the pods of the jobs
+const workspaceVolumeName = "workspace"
+
+// CreateWorkspace creates the PersistentVolumeClaim for the workspace of an action. The claim is labeled with the
+// Keptn context and the event, such that it can be identified later on
+func (k8s *K8sImpl) CreateWorkspace(
+	name string, action *config.Action, actionIndex int, jobSettings JobSettings, jsonEventData interface{},
+	namespace string,
+) error {
+	workspace := action.Workspace
+
+	size, err := resource.ParseQuantity(workspace.Size)
+	if err != nil {
+		return fmt.Errorf("invalid size %s of workspace: %w", workspace.Size, err)
+	}
+
+	jobDetails := JobDetails{Action: action, Task: &config.Task{}, ActionIndex: actionIndex}
+	generatedLabels, err := generateK8sJobLabels(jobDetails, jsonEventData, jobSettings.JesDeploymentName)
+	if err != nil {
+		return fmt.Errorf("unable to generate workspace labels: %w", err)
+	}
+
+	// The workspace belongs to the action, so the task labels are left out
+	labels := make(map[string]string)
+	for key, value := range jobSettings.JobLabels {
+		labels[key] = value
+	}
+	for _, key := range []string{
+		"app.kubernetes.io/managed-by", "keptn.sh/context", "keptn.sh/event-id", "keptn.sh/jes-action",
+	} {
+		labels[key] = generatedLabels[key]
+	}
+	labels["keptn.sh/jes-action-index"] = strconv.Itoa(actionIndex)
+
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			AccessModes: []v1.PersistentVolumeAccessMode{v1.PersistentVolumeAccessMode(workspace.GetAccessMode())},
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: size,
+				},
+			},
+		},
+	}
+
+	if workspace.StorageClassName != "" {
+		claim.Spec.StorageClassName = &workspace.StorageClassName
+	}
+
+	_, err = k8s.clientset.CoreV1().PersistentVolumeClaims(namespace).Create(context.TODO(), claim, metav1.CreateOptions{})
+	return err
+}
+
+// DeleteWorkspace deletes the PersistentVolumeClaim of the workspace of an action. Kubernetes keeps the claim until
+// all pods that use it are deleted
+func (k8s *K8sImpl) DeleteWorkspace(name string, namespace string) error {
+	return k8s.clientset.CoreV1().PersistentVolumeClaims(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+}
+
+// addWorkspace adds the volume of the workspace to the pod and mounts it into the job container
+func addWorkspace(podSpec *v1.PodSpec, claimName string, mountPath string) {
+	podSpec.Volumes = append(podSpec.Volumes, v1.Volume{
+		Name: workspaceVolumeName,
+		VolumeSource: v1.VolumeSource{
+			PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{
+				ClaimName: claimName,
+			},
+		},
+	})
+
+	for index := range podSpec.Containers {
+		podSpec.Containers[index].VolumeMounts = append(podSpec.Containers[index].VolumeMounts, v1.VolumeMount{
+			Name:      workspaceVolumeName,
+			MountPath: mountPath,
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package k8sutils
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	keptnv2 "github.com/keptn/go-utils/pkg/lib/v0_2_0"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"keptn-contrib/job-executor-service/pkg/config"
+)
+
+func TestCreateAndDeleteWorkspace(t *testing.T) {
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	action := &config.Action{
+		Name:      "Build and test",
+		Workspace: &config.Workspace{Size: "1Gi", StorageClassName: "standard"},
+	}
+
+	jobSettings := JobSettings{
+		JobLabels:         map[string]string{"team": "platform"},
+		JesDeploymentName: "job-executor-service",
+	}
+
+	err := k8s.CreateWorkspace("workspace-1", action, 2, jobSettings, eventAsInterface, testNamespace)
+	require.NoError(t, err)
+
+	claim, err := k8sClientSet.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.TODO(), "workspace-1", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"team":                         "platform",
+		"app.kubernetes.io/managed-by": "job-executor-service",
+		"keptn.sh/context":             "138f7bf1-f027-42c4-b705-9033b5f5871e",
+		"keptn.sh/event-id":            "4fe1eed1-49e2-49a9-91af-a42c8b0f7811",
+		"keptn.sh/jes-action":          "Build_and_test",
+		"keptn.sh/jes-action-index":    "2",
+	}, claim.Labels)
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, claim.Spec.AccessModes)
+	assert.Equal(t, resource.MustParse("1Gi"), claim.Spec.Resources.Requests[corev1.ResourceStorage])
+	require.NotNil(t, claim.Spec.StorageClassName)
+	assert.Equal(t, "standard", *claim.Spec.StorageClassName)
+
+	err = k8s.DeleteWorkspace("workspace-1", testNamespace)
+	require.NoError(t, err)
+
+	_, err = k8sClientSet.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.TODO(), "workspace-1", metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+func TestCreateWorkspaceInvalidSize(t *testing.T) {
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	k8s := K8sImpl{clientset: k8sfake.NewSimpleClientset()}
+
+	action := &config.Action{
+		Name:      "Build and test",
+		Workspace: &config.Workspace{Size: "one gigabyte"},
+	}
+
+	err := k8s.CreateWorkspace("workspace-1", action, 0, JobSettings{}, eventAsInterface, testNamespace)
+	assert.ErrorContains(t, err, "invalid size one gigabyte of workspace")
+}
+
+func TestCreateK8sJobWithWorkspace(t *testing.T) {
+	eventData := keptnv2.EventData{
+		Project: "sockshop",
+		Stage:   "dev",
+		Service: "carts",
+	}
+
+	var eventAsInterface interface{}
+	require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+	k8sClientSet := k8sfake.NewSimpleClientset()
+	k8s := K8sImpl{clientset: k8sClientSet}
+
+	err := k8s.CreateK8sJob(
+		"job-with-workspace",
+		JobDetails{
+			Action: &config.Action{
+				Name:      "Build and test",
+				Workspace: &config.Workspace{Size: "1Gi", MountPath: "/data"},
+			},
+			Task:               &config.Task{Name: "Build", Image: "alpine"},
+			WorkspaceClaimName: "workspace-1",
+		},
+		&eventData, JobSettings{
+			JobNamespace: testNamespace,
+			DefaultResourceRequirements: &corev1.ResourceRequirements{
+				Limits:   make(corev1.ResourceList),
+				Requests: make(corev1.ResourceList),
+			},
+			DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+			DefaultSecurityContext:    new(corev1.SecurityContext),
+		}, eventAsInterface, testNamespace,
+	)
+	require.NoError(t, err)
+
+	job, err := k8sClientSet.BatchV1().Jobs(testNamespace).Get(context.TODO(), "job-with-workspace", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	podSpec := job.Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "workspace",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "workspace-1"},
+		},
+	})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "workspace", MountPath: "/data"})
+	assert.NotContains(t, podSpec.InitContainers[0].VolumeMounts, corev1.VolumeMount{Name: "workspace", MountPath: "/data"})
+}
+
+func TestCreateK8sJobWorkspaceAtJobVolumeMountPath(t *testing.T) {
+	tests := []struct {
+		name          string
+		volume        *config.JobVolume
+		expectedError string
+	}{
+		{
+			name:          "default job volume mount path",
+			expectedError: "job volume of task Build can't be mounted at the mount path of the workspace /keptn",
+		},
+		{
+			name:   "custom job volume mount path",
+			volume: &config.JobVolume{MountPath: "/files"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var eventAsInterface interface{}
+			require.NoError(t, json.Unmarshal([]byte(testTriggeredEvent), &eventAsInterface))
+
+			k8s := K8sImpl{clientset: k8sfake.NewSimpleClientset()}
+
+			err := k8s.CreateK8sJob(
+				"job-with-workspace",
+				JobDetails{
+					Action: &config.Action{
+						Name:      "Build and test",
+						Workspace: &config.Workspace{Size: "1Gi", MountPath: "/keptn"},
+					},
+					Task:               &config.Task{Name: "Build", Image: "alpine", Volume: test.volume},
+					WorkspaceClaimName: "workspace-1",
+				},
+				&keptnv2.EventData{Project: "sockshop", Stage: "dev", Service: "carts"}, JobSettings{
+					JobNamespace: testNamespace,
+					DefaultResourceRequirements: &corev1.ResourceRequirements{
+						Limits:   make(corev1.ResourceList),
+						Requests: make(corev1.ResourceList),
+					},
+					DefaultPodSecurityContext: new(corev1.PodSecurityContext),
+					DefaultSecurityContext:    new(corev1.SecurityContext),
+				}, eventAsInterface, testNamespace,
+			)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
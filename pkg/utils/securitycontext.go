@@ -192,7 +192,7 @@ func VerifySecurityConfiguration(config *config.Config, allowPrivilegedJobs bool
 	emptyPodSecurityContext := new(v1.PodSecurityContext)
 
 	for _, action := range config.Actions {
-		for _, task := range action.Tasks {
+		for _, task := range action.AllTasks() {
 			taskSecurityContext := BuildSecurityContext(emptySecurityContext, task.SecurityContext)
 
 			err := VerifySecurityContext(emptyPodSecurityContext, taskSecurityContext, allowPrivilegedJobs)
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// VolumeSourceAllowList contains glob patterns of the ConfigMaps and Secrets that tasks are allowed to mount as
+// volumes. The patterns are matched against configmap/<name> and secret/<name>. Unlike the image allowlist, an empty
+// list doesn't allow anything, since mounting arbitrary secrets into job workloads is sensitive
+type VolumeSourceAllowList struct {
+	patterns []glob.Glob
+}
+
+// BuildVolumeSourceAllowList creates a VolumeSourceAllowList from a comma separated string that is present as
+// environment variable
+func BuildVolumeSourceAllowList(envVariable string) (*VolumeSourceAllowList, error) {
+	var patterns []glob.Glob
+	for _, str := range strings.Split(envVariable, ",") {
+		str = strings.TrimSpace(str)
+		if str == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(str, "configmap/") && !strings.HasPrefix(str, "secret/") {
+			return nil, fmt.Errorf("volume source pattern %s must start with configmap/ or secret/", str)
+		}
+
+		compiledGlob, err := glob.Compile(str)
+		if err != nil {
+			return nil, err
+		}
+
+		patterns = append(patterns, compiledGlob)
+	}
+
+	return &VolumeSourceAllowList{
+		patterns: patterns,
+	}, nil
+}
+
+// IsConfigMapAllowed returns true if the ConfigMap with the given name matches one of the patterns of the list
+func (l *VolumeSourceAllowList) IsConfigMapAllowed(name string) bool {
+	return l.contains("configmap/" + name)
+}
+
+// IsSecretAllowed returns true if the Secret with the given name matches one of the patterns of the list
+func (l *VolumeSourceAllowList) IsSecretAllowed(name string) bool {
+	return l.contains("secret/" + name)
+}
+
+func (l *VolumeSourceAllowList) contains(entry string) bool {
+	if l == nil {
+		return false
+	}
+
+	for _, pattern := range l.patterns {
+		if pattern.Match(entry) {
+			return true
+		}
+	}
+
+	return false
+}
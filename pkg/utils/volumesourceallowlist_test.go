@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVolumeSourceAllowList(t *testing.T) {
+	allowList, err := BuildVolumeSourceAllowList("configmap/*, secret/kubeconfig,secret/tls-*,")
+	require.NoError(t, err)
+
+	assert.True(t, allowList.IsConfigMapAllowed("tool-config"))
+	assert.True(t, allowList.IsSecretAllowed("kubeconfig"))
+	assert.True(t, allowList.IsSecretAllowed("tls-client"))
+	assert.False(t, allowList.IsSecretAllowed("job-service-keptn-secrets"))
+	assert.False(t, allowList.IsSecretAllowed("tool-config"))
+}
+
+func TestEmptyVolumeSourceAllowList(t *testing.T) {
+	allowList, err := BuildVolumeSourceAllowList("")
+	require.NoError(t, err)
+
+	assert.False(t, allowList.IsConfigMapAllowed("tool-config"))
+	assert.False(t, allowList.IsSecretAllowed("kubeconfig"))
+
+	var nilAllowList *VolumeSourceAllowList
+	assert.False(t, nilAllowList.IsSecretAllowed("kubeconfig"))
+}
+
+func TestInvalidVolumeSourceAllowList(t *testing.T) {
+	_, err := BuildVolumeSourceAllowList("kubeconfig")
+	assert.ErrorContains(t, err, "volume source pattern kubeconfig must start with configmap/ or secret/")
+
+	_, err = BuildVolumeSourceAllowList("secret/[")
+	assert.Error(t, err)
+}